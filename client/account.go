@@ -0,0 +1,125 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// the derivation path of the first account of a mnemonic in most ethereum wallets
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// ImportPrivateKey adds the hex private key hexKey to ks, encrypted with password
+func ImportPrivateKey(ks *keystore.KeyStore, hexKey string, password string) (accounts.Account, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("invalid private key: %s", err)
+	}
+	return ks.ImportECDSA(key, password)
+}
+
+// ImportMnemonic adds the key at path derived from a bip-39 mnemonic and passphrase to ks, encrypted with password
+func ImportMnemonic(ks *keystore.KeyStore, mnemonic string, passphrase string, path string, password string) (accounts.Account, error) {
+	key, err := MnemonicKey(mnemonic, passphrase, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return ks.ImportECDSA(key, password)
+}
+
+// MnemonicKey derives the key at path, eg. m/44'/60'/0'/0/0, from an english bip-39 mnemonic and its
+// passphrase, which is empty if the wallet did not ask for one. the words and checksum are checked, so a
+// mistyped mnemonic is an error rather than a different key
+func MnemonicKey(mnemonic string, passphrase string, path string) (*ecdsa.PrivateKey, error) {
+	words := strings.Fields(strings.ToLower(norm.NFKD.String(mnemonic)))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words, not %d", len(words))
+	}
+	for _, word := range words {
+		if _, ok := bip39.GetWordIndex(word); !ok {
+			return nil, fmt.Errorf("only english mnemonics are supported: %q is not a bip-39 word", word)
+		}
+	}
+	mnemonic = strings.Join(words, " ")
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %s; check the words and their order", err)
+	}
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %s: %s", path, err)
+	}
+
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	seed := pbkdf2.Key([]byte(mnemonic), []byte(salt), 2048, 64, sha512.New)
+	return deriveKey(seed, derivationPath)
+}
+
+// the first index of a hardened child key
+const hardenedOffset = 0x80000000
+
+// deriveKey derives the private key at path from a bip-32 seed
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(n) >= 0 {
+		return nil, errors.New("invalid seed")
+	}
+
+	for _, index := range path {
+		var data []byte
+		if index >= hardenedOffset {
+			data = append([]byte{0}, math.PaddedBigBytes(key, 32)...)
+		} else {
+			priv, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = compressPubkey(&priv.PublicKey)
+		}
+		indexBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(indexBytes, index)
+		data = append(data, indexBytes...)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("no key at index %d of the path; use the next one", index)
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, n)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("no key at index %d of the path; use the next one", index)
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+// the 33 byte compressed form of pub
+func compressPubkey(pub *ecdsa.PublicKey) []byte {
+	prefix := byte(2)
+	if pub.Y.Bit(0) == 1 {
+		prefix = 3
+	}
+	return append([]byte{prefix}, math.PaddedBigBytes(pub.X, 32)...)
+}
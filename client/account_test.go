@@ -0,0 +1,76 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestMnemonicKey(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	key, err := MnemonicKey(mnemonic, "", DefaultDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if address := crypto.PubkeyToAddress(key.PublicKey); address != expected {
+		t.Fatalf("got %s expected %s", address.Hex(), expected.Hex())
+	}
+
+	// the words are normalised, so case and spacing do not change the key
+	key, err = MnemonicKey("  Abandon abandon abandon abandon abandon abandon\nabandon abandon abandon abandon abandon ABOUT", "", DefaultDerivationPath)
+	if err != nil || crypto.PubkeyToAddress(key.PublicKey) != expected {
+		t.Fatalf("got %v for the mnemonic in another case, expected %s", err, expected.Hex())
+	}
+
+	// a passphrase derives another key
+	key, err = MnemonicKey(mnemonic, "TREZOR", DefaultDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := crypto.PubkeyToAddress(key.PublicKey); address == expected {
+		t.Fatal("got the same key with a passphrase as without")
+	}
+
+	invalid := []struct {
+		name     string
+		mnemonic string
+		path     string
+	}{
+		{"2 words", "abandon about", DefaultDerivationPath},
+		{"unknown word", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abut", DefaultDerivationPath},
+		{"bad checksum", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", DefaultDerivationPath},
+		{"invalid path", mnemonic, "m/44'/x"},
+	}
+	for _, test := range invalid {
+		if _, err := MnemonicKey(test.mnemonic, "", test.path); err == nil {
+			t.Errorf("accepted %s", test.name)
+		}
+	}
+}
+
+func TestImportPrivateKey(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "keystore")
+	defer os.RemoveAll(dir)
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+
+	key, _ := crypto.GenerateKey()
+	account, err := ImportPrivateKey(ks, "0x"+common.Bytes2Hex(crypto.FromECDSA(key))+"\n", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Address != crypto.PubkeyToAddress(key.PublicKey) || !ks.HasAddress(account.Address) {
+		t.Fatalf("imported %s", account.Address.Hex())
+	}
+	if err := ks.Unlock(account, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ImportPrivateKey(ks, "0x1234", "secret"); err == nil {
+		t.Fatal("imported an invalid key")
+	}
+}
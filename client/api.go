@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// Api is the http api for inspecting and controlling a running relayer
+//
+//	GET  /status                   status of every chain
+//	GET  /deposits?chain=&limit=   recent deposits, newest first
+//	GET  /deposits/<tx hash>       one deposit
+//	POST /deposits/<tx hash>/relay queue a deposit to be relayed again, unless it was relayed or executed
+//	POST /chains/<name>/pause      stop relaying deposits from a chain
+//	POST /chains/<name>/resume
+//	POST /chains/<name>/rescan?from=<block>
+//
+// every request must have the header "Authorization: Bearer <token>"
+type Api struct {
+	token  string
+	chains []*Chain
+}
+
+func NewApi(token string, chains []*Chain) (*Api, error) {
+	if token == "" {
+		return nil, errors.New("the api needs a token")
+	}
+	return &Api{token: token, chains: chains}, nil
+}
+
+// timeouts of the api server, so a slow or idle client cannot hold connections open
+const (
+	apiReadHeaderTimeout = 5 * time.Second
+	apiReadTimeout       = 10 * time.Second
+	apiWriteTimeout      = 30 * time.Second
+	apiIdleTimeout       = time.Minute
+	apiShutdownTimeout   = 5 * time.Second
+)
+
+// Serve serves the api at addr until ctx is cancelled, then lets the requests being handled finish
+func (a *Api) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           a,
+		ReadHeaderTimeout: apiReadHeaderTimeout,
+		ReadTimeout:       apiReadTimeout,
+		WriteTimeout:      apiWriteTimeout,
+		IdleTimeout:       apiIdleTimeout,
+	}
+	stopped, shutDown := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(shutDown)
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+			defer cancel()
+			srv.Shutdown(shutdownCtx)
+		case <-stopped:
+		}
+	}()
+
+	err := srv.ListenAndServe()
+	close(stopped)
+	<-shutDown
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJson(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, format string, a ...interface{}) {
+	writeJson(w, code, apiError{Error: fmt.Sprintf(format, a...)})
+}
+
+func (a *Api) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1
+}
+
+func (a *Api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "status":
+		writeJson(w, http.StatusOK, ChainStatuses())
+	case r.Method == http.MethodGet && len(parts) == 1 && parts[0] == "deposits":
+		a.deposits(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "deposits":
+		a.deposit(w, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "deposits" && parts[2] == "relay":
+		a.relay(w, r, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "chains":
+		a.control(w, r, parts[1], parts[2])
+	default:
+		writeError(w, http.StatusNotFound, "no such endpoint: %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (a *Api) deposits(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit %q", l)
+			return
+		}
+		limit = n
+	}
+	writeJson(w, http.StatusOK, store.Recent(r.URL.Query().Get("chain"), limit))
+}
+
+func (a *Api) deposit(w http.ResponseWriter, hash string) {
+	record := store.Get(common.HexToHash(hash))
+	if record == nil {
+		writeError(w, http.StatusNotFound, "deposit %s not found", hash)
+		return
+	}
+	writeJson(w, http.StatusOK, record)
+}
+
+// queue a deposit in the store to be relayed again, eg. after it failed; deposits that were relayed
+// or executed are left alone, as relaying them again would pay out twice
+func (a *Api) relay(w http.ResponseWriter, r *http.Request, hash string) {
+	record := store.Get(common.HexToHash(hash))
+	if record == nil {
+		writeError(w, http.StatusNotFound, "deposit %s not found", hash)
+		return
+	}
+	if record.Status == DepositRelayed || record.Status == DepositExecuted {
+		writeError(w, http.StatusConflict, "deposit %s was already %s", hash, record.Status)
+		return
+	}
+	origin := FindChainByName(record.Origin, a.chains)
+	if origin == nil {
+		writeError(w, http.StatusBadRequest, "origin chain %s of deposit is not being listened to", record.Origin)
+		return
+	}
+
+	logger.With(logger.Fields{"chain": origin.Name, "deposit": record.TxHash.Hex()}).Info("queueing deposit to be relayed again from the api")
+	err := pool.Enqueue(r.Context(), origin, a.chains, record.event())
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "could not queue deposit: %s", err)
+		return
+	}
+	writeJson(w, http.StatusAccepted, store.Get(record.TxHash))
+}
+
+func (a *Api) control(w http.ResponseWriter, r *http.Request, name string, action string) {
+	if FindChainByName(name, a.chains) == nil {
+		writeError(w, http.StatusNotFound, "chain %s not found", name)
+		return
+	}
+
+	switch action {
+	case "pause":
+		Pause(name)
+		logger.With(logger.Fields{"chain": name}).Warn("paused from the api")
+	case "resume":
+		Resume(name)
+		logger.With(logger.Fields{"chain": name}).Info("resumed from the api")
+	case "rescan":
+		from, ok := new(big.Int).SetString(r.URL.Query().Get("from"), 10)
+		if !ok || from.Sign() < 0 {
+			writeError(w, http.StatusBadRequest, "rescan needs a block number to start from, eg. ?from=7000000")
+			return
+		}
+		Rescan(name, from)
+		logger.With(logger.Fields{"chain": name}).Info("rescan from block %s requested from the api", from)
+	default:
+		writeError(w, http.StatusNotFound, "unknown action %s", action)
+		return
+	}
+
+	for _, s := range ChainStatuses() {
+		if s.Name == name {
+			writeJson(w, http.StatusOK, s)
+			return
+		}
+	}
+	writeJson(w, http.StatusOK, ChainStatus{Name: name, Paused: isPaused(name)})
+}
@@ -1,68 +1,84 @@
-package client 
+package client
 
 import (
-	"fmt"
-	"time"
+	"context"
 	"encoding/hex"
+	"fmt"
 	"math/big"
-	"context"
-	"log"
-	"strings"
-	"os"
-	"os/signal"
-	"syscall"
 	"sync"
-	"strconv"
+	"time"
 
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/ChainSafe/ChainBridge/logger"
 )
 
 /* global variables */
-var events *Events // events to listen for
+var events *Events          // events to listen for
 var keys *keystore.KeyStore // keystore; used to sign txs
-var flags map[string]bool // command line flags
-var logsRead = map[string]bool{}
+var flags map[string]bool   // command line flags
+
+// the logs each chain has read and the block of each, so a log that comes back in the same scan is only
+// handled once. logs of blocks that have been fully scanned are forgotten, as they are only read again on a rescan
+var logsMu sync.Mutex
+var logsRead = map[string]map[string]uint64{}
 
 type Chain struct {
-	Name string 						`json:"name"`
-	Url string 							`json:"url"`
-	Id *big.Int 						`json:"id,omitempty"`
-	Contract *common.Address 			`json:"contractAddr"`
-	GasPrice *big.Int 					`json:"gasPrice"`
-	From *common.Address 				`json:"from"`
-	Password string 					`json:"password,omitempty"`
-	Client *ethclient.Client 			`json:"client,omitempty"`
-	Nonce uint64 						`json:"nonce,omitempty"`
-	StartBlock *big.Int 				`json:"startBlock,omitempty"`
+	Name           string            `json:"name"`
+	Url            string            `json:"url"`
+	Id             *big.Int          `json:"id,omitempty"`
+	Contract       *common.Address   `json:"contractAddr"`
+	GasPrice       *big.Int          `json:"gasPrice"`
+	From           *common.Address   `json:"from"`
+	Signer         Signer            `json:"-"` // signs txs sent from From
+	Client         *ethclient.Client `json:"client,omitempty"`
+	Rpc            *RpcClient        `json:"-"`               // for calls ethclient has no method for; nil over websocket or ipc
+	Nonce          uint64            `json:"nonce,omitempty"` // the next nonce to send a tx with, unless the node's pending nonce is higher; only used under sendLock
+	StartBlock     *big.Int          `json:"startBlock,omitempty"`
+	MaxBlockRange  uint64            `json:"maxBlockRange,omitempty"`
+	Endpoints      []*Endpoint       `json:"urls,omitempty"`          // rpc endpoints to fail over between; Url is used if empty
+	MaxLag         uint64            `json:"maxLag,omitempty"`        // endpoints more blocks than this behind the others are avoided
+	RpcPolicy      *RpcPolicy        `json:"rpc,omitempty"`           // rate limit and retries of rpc requests
+	MinBalance     *big.Int          `json:"minBalance,omitempty"`    // alert when the contract holds less than this, in wei
+	WalletWarning  uint64            `json:"walletWarning,omitempty"` // warn when the relayer account can pay gas for fewer withdraws than this
+	WalletCritical uint64            `json:"walletCritical,omitempty"`
+	ContractType   string            `json:"contractType,omitempty"`
+	Origin         *Chain            `json:"-"` // for a wrapped contract, the chain that holds the locked ether; for a home contract, its foreign chain
 }
 
 type Withdrawal struct {
 	Recipient string
-	Value *big.Int
+	Value     *big.Int
 	FromChain string
-	TxHash string
-	Data string
+	TxHash    string
+	Data      string
 }
 
 // events to listen for
 type Events struct {
-	DepositId string
-  	CreationId string
- 	WithdrawId string
-	BridgeFundedId string
-	PaidId string
+	DepositId           string
+	CreationId          string
+	WithdrawId          string
+	BridgeFundedId      string
+	PaidId              string
+	SignedForWithdrawId string
+	AuthorityAddedId    string
+	AuthorityRemovedId  string
+	ThresholdUpdatedId  string
+	BridgeSetId         string
+	ForeignWithdrawId   string // Withdraw(uint256) of the Foreign contract
+	MintId              string // Mint(address,uint256,uint256,bytes32) of the Wrapped contract
+	BurnId              string // Burn(address,uint256,uint256) of the Wrapped contract
 }
 
 /****** helpers ********/
 
 // pads zeroes on front of a string until it's 32 bytes or 64 hex characters long
-func padTo32Bytes(s string) (string) {
+func padTo32Bytes(s string) string {
 	l := len(s)
 	for {
 		if l == 64 {
@@ -74,19 +90,19 @@ func padTo32Bytes(s string) (string) {
 	}
 }
 
-func padBigTo32Bytes(n *big.Int) (string) {
+func padBigTo32Bytes(n *big.Int) string {
 	nBytes := n.Bytes()
 	nHexStr := hex.EncodeToString(nBytes)
 	return padTo32Bytes(nHexStr)
 }
 
-func padIntTo32Bytes(n int64) (string) {
+func padIntTo32Bytes(n int64) string {
 	nBig := new(big.Int).SetInt64(n)
 	return padBigTo32Bytes(nBig)
 }
 
 // set w.Data
-func setWithdrawalData(w *Withdrawal) (*Withdrawal) {
+func setWithdrawalData(w *Withdrawal) *Withdrawal {
 	valueBytes := w.Value.Bytes()
 	valueString := hex.EncodeToString(valueBytes)
 	valueString = padTo32Bytes(valueString)
@@ -101,19 +117,23 @@ func setWithdrawalData(w *Withdrawal) (*Withdrawal) {
 // return index i if chain in allChains, otherwise return -1
 func findChainIndex(id *big.Int, allChains []*Chain) int {
 	for i, chain := range allChains {
-		if chain.Id.Cmp(id) == 0 { return i }
+		if chain.Id.Cmp(id) == 0 {
+			return i
+		}
 	}
 	return -1
 }
 
-func FindChain(id *big.Int, allChains []*Chain) (*Chain) {
+func FindChain(id *big.Int, allChains []*Chain) *Chain {
 	for _, chain := range allChains {
-		if chain.Id.Cmp(id) == 0 { return chain }
+		if chain.Id.Cmp(id) == 0 {
+			return chain
+		}
 	}
 	return nil
 }
 
-func FindChainByName(name string, allChains []*Chain) (*Chain) {
+func FindChainByName(name string, allChains []*Chain) *Chain {
 	for _, chain := range allChains {
 		if chain.Name == name {
 			return chain
@@ -124,105 +144,156 @@ func FindChainByName(name string, allChains []*Chain) (*Chain) {
 
 /***** client functions ******/
 
-func Filter(chain *Chain, allChains []*Chain, filter *ethereum.FilterQuery, logsDone chan bool) {
-	logs, err := chain.Client.FilterLogs(context.Background(), *filter)
-	if err != nil {
-		logger.Error("%s", err)
-	}
-
-	if len(logs) != 0 {
-		go ReadLogs(chain, allChains, logs, logsDone)
-	}
+// scan blocks from to `to` on chain in chunks and read the logs found, saving the last block
+// scanned after each chunk, until ctx is cancelled. returns the last block that was fully scanned, or nil if none were
+func Filter(ctx context.Context, chain *Chain, router *Router, scanner *Scanner, from *big.Int, to *big.Int) (*big.Int, error) {
+	var lastBlock *big.Int
+	err := scanner.Scan(ctx, from.Uint64(), to.Uint64(), func(logs []types.Log, start, end uint64) error {
+		// stop between chunks when shutting down; the rest are scanned on the next start
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(logs) != 0 {
+			ReadLogs(chain, router, logs)
+		}
+		// a deposit may not have been queued, so the chunk is scanned again on the next start
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-	logsDone <- true
+		lastBlock = new(big.Int).SetUint64(end)
+		forgetLogs(chain.Name, func(block uint64) bool { return block <= end })
+		err := SaveCheckpoint(chain, lastBlock)
+		if err != nil {
+			logger.Error("could not save last block on %s: %s", chain.Name, err)
+		}
+		return nil
+	})
+	return lastBlock, err
 }
 
-func ReadLogs(chain *Chain, allChains []*Chain, logs []types.Log, logsDone chan bool) {
-	for _, log := range logs {
-		txHash := log.TxHash.Hex()
-		if(!logsRead[txHash]) {
-			logger.Event("logs found on %s at block %d", chain.Name, log.BlockNumber)
-			logger.Event("contract address: %s", log.Address.Hex())
-			for _, topics := range log.Topics {
-				topic := topics.Hex()
-				if strings.Compare(topic, events.DepositId) == 0 { 
-					logger.Event("deposit event: tx hash: %s", txHash)
-					withdrawDone := make(chan bool)
-					go HandleDeposit(chain, allChains, log.TxHash, withdrawDone)
-					<-withdrawDone
-			 	} else if strings.Compare(topic, events.CreationId) == 0 {
-					logger.Event("bridge contract creation")
-				} else if strings.Compare(topic, events.WithdrawId) == 0 {
-					logger.Event("withdraw event: tx hash: %s", txHash)
-					printWithdraw(chain, log.TxHash)
-				} else if strings.Compare(topic, events.BridgeFundedId) == 0 {
-					logger.Event("funded bridge event: tx hash: %s", txHash)
-				} else if strings.Compare(topic, events.PaidId) == 0 {
-					logger.Event("bridge paid event: tx hash: %s", txHash)
-				}
-			}
-			logsRead[txHash] = true
-		}
+// whether log has not been read on chain yet, marking it read
+func markRead(name string, log types.Log) bool {
+	logsMu.Lock()
+	defer logsMu.Unlock()
+	read, ok := logsRead[name]
+	if !ok {
+		read = map[string]uint64{}
+		logsRead[name] = read
+	}
+	id := fmt.Sprintf("%s:%d", log.TxHash.Hex(), log.Index)
+	if _, ok := read[id]; ok {
+		return false
 	}
-	logsDone <- true
+	read[id] = log.BlockNumber
+	return true
 }
 
-func waitOnPending(chain *Chain, txHash common.Hash) (*types.Transaction) {
-	for {
-		tx, isPending, _ := chain.Client.TransactionByHash(context.Background(), txHash)
-		if !isPending { return tx }
+// forget the logs read on chain in the blocks matching in, so they are handled if read again
+func forgetLogs(name string, in func(block uint64) bool) {
+	logsMu.Lock()
+	defer logsMu.Unlock()
+	for id, block := range logsRead[name] {
+		if in(block) {
+			delete(logsRead[name], id)
+		}
 	}
 }
 
-func printWithdraw(chain *Chain, txHash common.Hash) {
-	tx := waitOnPending(chain, txHash)
+// pass each log to the handler registered for its contract and event
+func ReadLogs(chain *Chain, router *Router, logs []types.Log) {
+	for _, log := range logs {
+		if !markRead(chain.Name, log) {
+			continue
+		}
 
-	data := hex.EncodeToString(tx.Data())
-	if len(data) > 136 {
-		receiver := data[32:72];
-		value := data[72:136]
-		fromChain := data[136:200]
+		if router.Route(log) == nil {
+			// only logs of other contracts in -a mode, or events the router does not know
+			if flags["v"] {
+				logger.Info("unhandled log on %s at block %d from contract %s", chain.Name, log.BlockNumber, log.Address.Hex())
+			}
+			continue
+		}
 
-		logger.Event("receiver: 0x%s", receiver) 
-		logger.Event("value: %s", value) // in hexidecimal
-		logger.Event("from chain: %s", fromChain) // in hexidecimal
+		// the handler logs the event itself, with its fields
+		_, err := router.Dispatch(chain, log)
+		if err != nil {
+			logFor(chain, log).With(logger.Fields{"contract": log.Address.Hex()}).Error("%s", err)
+		}
 	}
 }
 
-func HandleDeposit(chain *Chain, allChains []*Chain, txHash common.Hash, withdrawDone chan bool) {
-	tx := waitOnPending(chain, txHash)
+// relay a deposit made on chain to its destination chain
+func HandleDeposit(chain *Chain, allChains []*Chain, deposit *DepositEvent) error {
+	dest, status, err := relayDeposit(chain, allChains, deposit)
+	store.SetStatus(deposit.TxHash, dest, status, err)
+	return err
+}
 
-	withdrawal := new(Withdrawal)
-	data := hex.EncodeToString(tx.Data())
+// send the withdraw or mint for a deposit, returning the name of the destination chain
+// and the status of the deposit afterwards
+func relayDeposit(chain *Chain, allChains []*Chain, deposit *DepositEvent) (string, string, error) {
+	idx := findChainIndex(deposit.ToChain, allChains)
+	if idx == -1 {
+		return "", DepositFailed, fmt.Errorf("could not find chain %s to withdraw to", deposit.ToChain)
+	}
 
-	if len(data) > 72 {
-		receiver := data[32:72]
-		toChain := data[72:136]
-		value := tx.Value()
+	dest := allChains[idx]
+	if dest.ContractType == ForeignContract {
+		return dest.Name, DepositFailed, fmt.Errorf("cannot withdraw on %s, it has a foreign contract", dest.Name)
+	}
+
+	// wrapped tokens are only backed by ether locked on the origin chain of the wrapped contract,
+	// and burnt tokens can only be released there
+	if dest.ContractType == WrappedContract && (dest.Origin == nil || dest.Origin.Id.Cmp(chain.Id) != 0) {
+		return dest.Name, DepositFailed, fmt.Errorf("cannot mint on %s for a deposit on %s, it is not the origin of the wrapped token", dest.Name, chain.Name)
+	}
+	if chain.ContractType == WrappedContract && (chain.Origin == nil || chain.Origin.Id.Cmp(dest.Id) != 0) {
+		return dest.Name, DepositFailed, fmt.Errorf("cannot release burn on %s to %s, it is not the origin of the wrapped token", chain.Name, dest.Name)
+	}
 
-		to, _ := strconv.Atoi(toChain)
-		logger.Event("receiver: 0x%s", receiver) 
-		logger.Event("value: %d", value) // in hexidecimal
-		logger.Event("to chain: %d", to) // in hexidecimal
+	// a home contract only pays out deposits made on its foreign chain, which only deposits to it
+	if dest.ContractType == HomeContract && (dest.Origin == nil || dest.Origin.Id.Cmp(chain.Id) != 0) {
+		return dest.Name, DepositFailed, fmt.Errorf("cannot withdraw on %s for a deposit on %s, it is not the foreign chain of the home contract", dest.Name, chain.Name)
+	}
+	if chain.ContractType == ForeignContract && (dest.ContractType != HomeContract || dest.Origin == nil || dest.Origin.Id.Cmp(chain.Id) != 0) {
+		return dest.Name, DepositFailed, fmt.Errorf("cannot withdraw on %s for a deposit on %s, it is not the home chain paired with it", dest.Name, chain.Name)
+	}
 
-		withdrawal.Recipient = data[32:72]
-		withdrawal.FromChain = toChain
-		withdrawal.Value = value
-		withdrawal.TxHash = txHash.Hex()[2:]
+	if dest.ContractType == WrappedContract {
+		logger.With(logger.Fields{"chain": chain.Name, "deposit": deposit.TxHash.Hex(), "block": deposit.BlockNumber, "dest": dest.Name}).Info("chain to mint on: %s", dest.Name)
+		status, err := relayStatus(Mint(dest, newWithdrawal(chain, deposit)))
+		return dest.Name, status, err
+	}
 
-		fromChain := new(big.Int)
-		fromChain.SetString(toChain, 16)
-		logger.Info("chain to withdraw to: %s", fromChain)
+	l := logger.With(logger.Fields{"chain": chain.Name, "deposit": deposit.TxHash.Hex(), "block": deposit.BlockNumber, "dest": dest.Name})
+	l.Info("chain to withdraw to: %s", dest.Name)
+	w := newWithdrawal(chain, deposit)
+	if !paysOut(dest) {
+		status, err := relayStatus(Withdraw(dest, w))
+		return dest.Name, status, err
+	}
 
-		idx := findChainIndex(fromChain, allChains)
+	// hold the withdraw back if the contract on dest cannot pay it out
+	liquidity := LiquidityOf(dest)
+	if !liquidity.Reserve(deposit.TxHash, deposit.Value) {
+		liquidity.Defer(w)
+		return dest.Name, DepositDeferred, nil
+	}
+	err := Withdraw(dest, w)
+	if err != nil {
+		liquidity.Release(deposit.TxHash)
+	}
+	status, err := relayStatus(err)
+	return dest.Name, status, err
+}
 
-		if idx == -1 {
-			logger.Error("could not find chain to withdraw to")
-		} else {
-			Withdraw(allChains[idx], withdrawal)
-		}
+// the status of a deposit after sending its withdraw or mint
+func relayStatus(err error) (string, error) {
+	if err != nil {
+		return DepositFailed, err
 	}
-	withdrawDone <- true
+	return DepositRelayed, nil
 }
 
 func FundPrompt(chain *Chain, ks *keystore.KeyStore) {
@@ -234,13 +305,13 @@ func FundPrompt(chain *Chain, ks *keystore.KeyStore) {
 	fmt.Println("note that funding of the bridge cannot be withdrawn")
 	fmt.Println("enter value of funding, in ether")
 	fmt.Scanln(&value)
-	if value == -1 { 
+	if value == -1 {
 		return
 	}
 	valBig := big.NewInt(value)
 	fmt.Println("confirm funding on chain", chain.Id, "with value", value, "ether")
 	fmt.Scanln(&confirm)
-	if confirm == -1 { 
+	if confirm == -1 {
 		return
 	}
 	FundBridge(chain, valBig)
@@ -256,12 +327,12 @@ func DepositPrompt(chain *Chain, ks *keystore.KeyStore) {
 	fmt.Println("type -1 to escape")
 	fmt.Println("enter value of deposit, in wei")
 	fmt.Scanln(&value)
-	if value == -1 { 
+	if value == -1 {
 		return
 	}
 	fmt.Println("enter chain id to withdraw on")
 	fmt.Scanln(&to)
-	if to == -1 { 
+	if to == -1 {
 		return
 	}
 
@@ -270,7 +341,7 @@ func DepositPrompt(chain *Chain, ks *keystore.KeyStore) {
 	toHex := fmt.Sprintf("%x", to)
 	fmt.Println("confirm deposit on chain", chain.Id, "with value", value, "wei, withdrawing to chain", to)
 	fmt.Scanln(&confirm)
-	if confirm == -1 { 
+	if confirm == -1 {
 		return
 	}
 	Deposit(chain, valBig, toHex)
@@ -286,18 +357,18 @@ func WithdrawToPrompt(chain *Chain, ks *keystore.KeyStore) {
 	fmt.Println("type -1 to escape")
 	fmt.Println("enter value of withdraw, in wei")
 	fmt.Scanln(&value)
-	if value == -1 { 
+	if value == -1 {
 		return
 	}
 	fmt.Println("enter chain id to withdraw on")
 	fmt.Scanln(&to)
-	if to == -1 { 
+	if to == -1 {
 		return
 	}
 
 	fmt.Println("confirm deposit on chain", chain.Id, "with value", value, "wei, withdrawing to chain", to)
 	fmt.Scanln(&confirm)
-	if confirm == -1 { 
+	if confirm == -1 {
 		return
 	}
 
@@ -330,83 +401,166 @@ func PayBridgePrompt(chain *Chain, ks *keystore.KeyStore) {
 	PayBridge(chain, valBig)
 }
 
+func BurnPrompt(chain *Chain, ks *keystore.KeyStore) {
+	keys = ks
+
+	var value int64
+	var confirm int64
+	if chain.ContractType != WrappedContract {
+		logger.Error("%s does not have a wrapped token contract", chain.Name)
+		return
+	}
+	if chain.Origin == nil {
+		logger.Error("origin chain of the wrapped token on %s is not loaded", chain.Name)
+		return
+	}
+
+	fmt.Println("\nburning wrapped tokens on chain", chain.Id, "to release ether on chain", chain.Origin.Id)
+	fmt.Println("type -1 to escape")
+	fmt.Println("enter value to burn, in wei")
+	fmt.Scanln(&value)
+	if value == -1 {
+		return
+	}
+
+	fmt.Println("confirm burn on chain", chain.Id, "with value", value, "wei, releasing on chain", chain.Origin.Id)
+	fmt.Scanln(&confirm)
+	if confirm == -1 {
+		return
+	}
+
+	valBig := big.NewInt(value)
+	Burn(chain, valBig, fmt.Sprintf("%x", chain.Origin.Id))
+}
+
+// how many times in a row the listener can fail to get the latest block or scan it before it stops,
+// so at least a minute of the node being unreachable
+const maxListenFailures = 60
+
 // main goroutine
-// starts a client to listen on every chain 
-func Listen(chain *Chain, ac []*Chain, e *Events, doneClient chan bool, ks *keystore.KeyStore, fl map[string]bool, wg *sync.WaitGroup) {
+// listens for events on chain until ctx is cancelled, then saves its checkpoint.
+// chain must already be connected, as its contract and start block are checked before listening.
+// returns an error if it cannot carry on, ie. if it fails to get the latest block or scan it
+// maxListenFailures times in a row
+func Listen(ctx context.Context, chain *Chain, ac []*Chain, e *Events, ks *keystore.KeyStore, fl map[string]bool) error {
 	// set up global vars
 	events = e
 	keys = ks
 	flags = fl
 	allChains := ac
 
-	// dial client
-	client, err := ethclient.Dial(chain.Url)
-	if err != nil {
-		log.Fatal(err)
-	}
-	chain.Client = client
-
 	logger.Info("listening at: %s", chain.Url)
 
+	// first block that has not been scanned yet
 	fromBlock := chain.StartBlock
+	// last block that was fully scanned
+	var lastBlock *big.Int
 
 	//lastBlocks[chain.Id] <- fromBlock
 	logger.Info("starting block on %s: %s", chain.Name, fromBlock)
-	filter := new(ethereum.FilterQuery)
-
-	c := make(chan os.Signal)
-    signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-    go func() {
-        <-c
-        Cleanup(chain, fromBlock, wg)
-        wg.Wait()
-        os.Exit(1)
-    }()
-
-	// every second, check for new logs and update block number
+
+	router := NewRouter()
+	RegisterContractRoutes(ctx, router, chain, allChains)
+
+	// only query for the registered contracts and events, unless reading logs from every contract
+	filter := router.Query()
+	if flags["a"] {
+		filter = ethereum.FilterQuery{}
+	}
+	scanner := NewScanner(chain, filter)
+
+	// withdraws sent before the last shutdown that were not mined yet
+	resumeTracking(chain)
+	// deposits found before the last shutdown that were not relayed yet
+	requeueSeen(ctx, chain, allChains)
+
+	// how often to reconcile the supply of a wrapped token against the ether locked on its origin chain
+	supplyCheckInterval := time.Minute
+	var lastSupplyCheck time.Time
+
+	// how often to check the balance of the contract and send withdraws deferred for lack of funds
+	liquidityCheckInterval := 30 * time.Second
+	var lastLiquidityCheck time.Time
+
+	// how often to check the relayer account can still pay for gas
+	walletCheckInterval := 30 * time.Second
+	var lastWalletCheck time.Time
+
+	// times in a row getting the latest block or scanning it failed, and the last error
+	failures := 0
+	var lastErr error
+
+	// every second, check for new blocks and scan them for logs
 	for {
-		filter.FromBlock = fromBlock
+		// the listener always asks for the latest block, and caches it for the tx trackers
+		failed := false
+		head, err := chainHead(ctx, chain, 0)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("could not get latest block on %s: %s", chain.Name, err)
+				failed, lastErr = true, err
+			}
+			setHead(chain, nil, err)
+		} else {
+			latestBlock.Set(bigFloat(head), chain.Name)
+			setHead(chain, head, nil)
+		}
 
-		// if not reading from all contracts, add the bridge contract address to the filter
-		if !flags["a"] {
-			contractArr := make([]common.Address, 1)
-			contractArr = append(contractArr, *chain.Contract)
-			filter.Addresses = contractArr
+		// go back to an earlier block if asked to by the admin api
+		if from := takeRescan(chain.Name); from != nil {
+			logger.Info("rescanning %s from block %s", chain.Name, from)
+			fromBlock = from
 		}
-		logsDone := make(chan bool)
-		go Filter(chain, allChains, filter, logsDone)
 
-		block, err := client.BlockByNumber(context.Background(), nil)
-		if err != nil {
-			// could not get block with ethclient.. trying http request
-			blockNum, err := getBlockNumber(chain.Url)
-			if err != nil {
-				logger.Error("getBlockNumber error: %s", err)
-			} else {
-				if len(blockNum) > 2 {
-					ok := false
-					fromBlock, ok = new(big.Int).SetString(blockNum[2:], 16)
-					if !ok {
-						logger.Error("Could not get block number")
-					}
-				}
+		if head != nil && head.Cmp(fromBlock) >= 0 && !isPaused(chain.Name) {
+			if flags["v"] {
+				logger.Info("latest block on %s: %s", chain.Name, head)
 			}
-			if flags["v"] { 
-				logger.Info("latest block on %s: %s", chain.Name, blockNum) 
+
+			last, err := Filter(ctx, chain, router, scanner, fromBlock, head)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("could not get logs on %s: %s", chain.Name, err)
+				failed, lastErr = true, err
 			}
-		} else if fromBlock != block.Number() {
-			if err != nil { 
-				logger.Error("%s", err) 
+			if last != nil {
+				lastBlock = last
+				fromBlock = new(big.Int).Add(last, big.NewInt(1))
+				blockLag.Set(bigFloat(new(big.Int).Sub(head, last)), chain.Name)
 			}
-			if flags["v"] { 
-				logger.Info("latest block on %s: %s", chain.Name, block.Number()) 
+		}
+
+		// the relayer account pays gas for every withdraw, so check it can still afford them
+		if chain.From != nil && time.Since(lastWalletCheck) >= walletCheckInterval {
+			_, err := CheckWallet(chain)
+			if err != nil {
+				logger.Error("could not check relayer account on %s: %s", chain.Name, err)
 			}
-			fromBlock = block.Number()
+			lastWalletCheck = time.Now()
+		}
+
+		if paysOut(chain) && time.Since(lastLiquidityCheck) >= liquidityCheckInterval {
+			LiquidityOf(chain).Check()
+			lastLiquidityCheck = time.Now()
 		}
 
-		<-logsDone
+		if chain.ContractType == WrappedContract && time.Since(lastSupplyCheck) >= supplyCheckInterval {
+			reconcileSupply(chain)
+			lastSupplyCheck = time.Now()
+		}
 
-		time.Sleep(1 * time.Second)
+		failures++
+		if !failed {
+			failures = 0
+		}
+		if failures >= maxListenFailures {
+			Cleanup(chain, lastBlock)
+			return fmt.Errorf("giving up on %s after failing %d times in a row: %s", chain.Name, failures, lastErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return Cleanup(chain, lastBlock)
+		case <-time.After(1 * time.Second):
+		}
 	}
- 	doneClient <- true
-}
\ No newline at end of file
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// the state of the listener on a chain, as shown by the admin api
+type ChainStatus struct {
+	Name       string    `json:"name"`
+	Connected  bool      `json:"connected"` // whether the last request for the latest block succeeded
+	Head       *big.Int  `json:"head"`
+	Checkpoint *big.Int  `json:"checkpoint"`
+	Paused     bool      `json:"paused"`
+	Error      string    `json:"error,omitempty"`
+	Updated    time.Time `json:"updated"`
+}
+
+var controlMu sync.Mutex
+var chainStatus = map[string]*ChainStatus{}
+var rescans = map[string]*big.Int{} // block each chain has been asked to rescan from
+
+// the status of chain, created if it does not exist; controlMu must be held
+func statusOf(name string) *ChainStatus {
+	s, ok := chainStatus[name]
+	if !ok {
+		s = &ChainStatus{Name: name}
+		chainStatus[name] = s
+	}
+	return s
+}
+
+// record the latest block of chain, or the error getting it
+func setHead(chain *Chain, head *big.Int, err error) {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	s := statusOf(chain.Name)
+	s.Connected = err == nil
+	s.Error = ""
+	if err != nil {
+		s.Error = err.Error()
+	} else {
+		s.Head = head
+	}
+	s.Updated = time.Now()
+}
+
+func setCheckpoint(chain *Chain, block *big.Int) {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	statusOf(chain.Name).Checkpoint = block
+}
+
+// ChainStatuses returns the status of every chain being listened to, by name
+func ChainStatuses() []ChainStatus {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	statuses := []ChainStatus{}
+	for _, s := range chainStatus {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Pause stops the listener on a chain from scanning new blocks, so no deposits on it are relayed
+// until it is resumed. it carries on from its checkpoint when resumed, so no deposits are missed.
+func Pause(name string) {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	statusOf(name).Paused = true
+}
+
+func Resume(name string) {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	statusOf(name).Paused = false
+}
+
+func isPaused(name string) bool {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	return statusOf(name).Paused
+}
+
+// Rescan makes the listener on a chain go back and scan again from block from.
+// deposits that are in the store as already relayed are not relayed again.
+func Rescan(name string, from *big.Int) {
+	forgetLogs(name, func(block uint64) bool { return block >= from.Uint64() })
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	rescans[name] = from
+}
+
+// the block chain has been asked to rescan from, or nil
+func takeRescan(name string) *big.Int {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	from := rescans[name]
+	delete(rescans, name)
+	return from
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Dial connects to the nodes of chain. over http, every call goes to the best of the chain's endpoints,
+// failing over to the others, and is timed and counted in the metrics; a websocket or ipc url is dialed
+// as it is, and cannot be used with other endpoints. over http, chain.Rpc is set to make calls ethclient
+// has no method for, or batches of calls, through the same endpoints.
+func Dial(chain *Chain) (*ethclient.Client, error) {
+	endpoints := endpointsOf(chain)
+	for _, e := range endpoints {
+		if !isHttp(e.Url) {
+			if len(endpoints) > 1 {
+				return nil, fmt.Errorf("%s is not an http endpoint; only http endpoints can fail over", e.Url)
+			}
+			return ethclient.Dial(e.Url)
+		}
+	}
+
+	transport := newFailoverTransport(chain, &rpcTransport{chain: chain.Name, next: http.DefaultTransport})
+	if len(endpoints) > 1 {
+		go transport.checkHeads()
+	}
+	httpClient := &http.Client{Transport: transport}
+	c, err := rpc.DialHTTPWithClient(endpoints[0].Url, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	chain.Rpc = NewRpcClient(endpoints[0].Url, httpClient)
+	return ethclient.NewClient(c), nil
+}
+
+// rpcTransport records the duration of every json-rpc request to a node and counts the failed ones
+type rpcTransport struct {
+	chain string
+	next  http.RoundTripper
+}
+
+func (t *rpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := "unknown"
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		method = rpcMethod(body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	rpcDuration.Observe(time.Since(start).Seconds(), t.chain, method)
+	if err != nil {
+		rpcErrors.Inc(t.chain, method)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		rpcErrors.Inc(t.chain, method)
+		return resp, nil
+	}
+
+	// json-rpc errors come back with status 200, so the body has to be checked
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		rpcErrors.Inc(t.chain, method)
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if rpcFailed(body) {
+		rpcErrors.Inc(t.chain, method)
+	}
+	return resp, nil
+}
+
+type rpcMessage struct {
+	Method string           `json:"method,omitempty"`
+	Error  *json.RawMessage `json:"error,omitempty"`
+}
+
+// the method of a json-rpc request, or "batch" for a batch of requests
+func rpcMethod(body []byte) string {
+	msg := new(rpcMessage)
+	if json.Unmarshal(body, msg) == nil && msg.Method != "" {
+		return msg.Method
+	}
+	batch := []rpcMessage{}
+	if json.Unmarshal(body, &batch) == nil {
+		return "batch"
+	}
+	return "unknown"
+}
+
+// whether a json-rpc response, or any response in a batch, is an error
+func rpcFailed(body []byte) bool {
+	msg := new(rpcMessage)
+	if json.Unmarshal(body, msg) == nil {
+		return msg.Error != nil
+	}
+	batch := []rpcMessage{}
+	if json.Unmarshal(body, &batch) == nil {
+		for _, m := range batch {
+			if m.Error != nil {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// an endpoint is unhealthy when its error rate is above this, or it is more than
+// the chain's maxLag blocks behind the best head seen on any endpoint of the chain
+const (
+	maxErrorRate  = 0.5
+	defaultMaxLag = 5
+)
+
+// how often the head of every endpoint is checked
+const healthCheckInterval = 15 * time.Second
+
+// an rpc endpoint of a chain
+type Endpoint struct {
+	Url       string  `json:"url"`
+	Priority  int     `json:"priority,omitempty"`  // endpoints with a lower priority are used first
+	RateLimit float64 `json:"rateLimit,omitempty"` // requests per second, overriding the rate limit of the chain
+	Burst     int     `json:"burst,omitempty"`
+
+	limiter   *tokenBucket
+	mu        sync.Mutex
+	head      uint64
+	latency   time.Duration
+	errorRate float64 // decays towards 0 with every successful request
+	lastError error
+}
+
+// record the outcome of a request to e
+func (e *Endpoint) record(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errorRate *= 0.8
+	if err != nil {
+		e.errorRate += 0.2
+		e.lastError = err
+		return
+	}
+	// smooth the latency so one slow request does not reorder the endpoints
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (e.latency*4 + latency) / 5
+	}
+}
+
+func (e *Endpoint) setHead(head uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.head = head
+}
+
+// host of the endpoint, used to label it; the path of hosted endpoints often holds an api key
+func (e *Endpoint) host() string {
+	u, err := url.Parse(e.Url)
+	if err != nil {
+		return "invalid"
+	}
+	return u.Host
+}
+
+// the endpoints of chain; just its url if it does not list any
+func endpointsOf(chain *Chain) []*Endpoint {
+	if len(chain.Endpoints) != 0 {
+		return chain.Endpoints
+	}
+	return []*Endpoint{{Url: chain.Url}}
+}
+
+func isHttp(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// failoverTransport sends every json-rpc request to the best endpoint of a chain, and on to the
+// next best if it fails: healthy endpoints first by priority and then latency, then the unhealthy ones
+type failoverTransport struct {
+	chain     string
+	endpoints []*Endpoint
+	maxLag    uint64
+	policy    *RpcPolicy
+	next      http.RoundTripper
+}
+
+func newFailoverTransport(chain *Chain, next http.RoundTripper) *failoverTransport {
+	maxLag := chain.MaxLag
+	if maxLag == 0 {
+		maxLag = defaultMaxLag
+	}
+	endpoints := endpointsOf(chain)
+	for _, e := range endpoints {
+		e.limiter = chain.RpcPolicy.limiter(e)
+	}
+	return &failoverTransport{chain: chain.Name, endpoints: endpoints, maxLag: maxLag, policy: chain.RpcPolicy, next: next}
+}
+
+// the endpoints in the order they should be tried
+func (t *failoverTransport) ranked() []*Endpoint {
+	var best uint64
+	for _, e := range t.endpoints {
+		e.mu.Lock()
+		if e.head > best {
+			best = e.head
+		}
+		e.mu.Unlock()
+	}
+
+	type ranking struct {
+		endpoint *Endpoint
+		healthy  bool
+		priority int
+		latency  time.Duration
+	}
+	rankings := make([]ranking, len(t.endpoints))
+	for i, e := range t.endpoints {
+		e.mu.Lock()
+		lagging := e.head != 0 && best-e.head > t.maxLag
+		rankings[i] = ranking{e, e.errorRate < maxErrorRate && !lagging, e.Priority, e.latency}
+		e.mu.Unlock()
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		a, b := rankings[i], rankings[j]
+		if a.healthy != b.healthy {
+			return a.healthy
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.latency < b.latency
+	})
+
+	endpoints := make([]*Endpoint, len(rankings))
+	for i, r := range rankings {
+		endpoints[i] = r.endpoint
+		up := 0.0
+		if r.healthy {
+			up = 1
+		}
+		rpcEndpointUp.Set(up, t.chain, r.endpoint.host())
+	}
+	return endpoints
+}
+
+// RoundTrip tries every endpoint in turn, and if they all fail with transient errors,
+// tries them all again after backing off, up to the number of retries of the chain's policy.
+// a signed tx is only sent once, to the best endpoint
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a tx that seemed to fail, eg. timed out, may still have reached the node. sent again, here or to
+	// another endpoint, it fails with "already known" or "nonce too low", and its deposit would then be
+	// relayed again in a new tx, paying it out twice
+	if rpcMethod(body) == "eth_sendRawTransaction" {
+		return t.tryEndpoints(req, body, t.ranked()[:1])
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.tryEndpoints(req, body, t.ranked())
+		if err == nil || !isTransient(err) || attempt >= t.policy.retries() {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt)
+		if s, ok := err.(*statusError); ok && s.retryAfter > delay {
+			delay = s.retryAfter
+		}
+		rpcRetries.Inc(t.chain)
+		logger.With(logger.Fields{"chain": t.chain}).Debug("rpc request failed, retrying in %s: %s", delay, err)
+		select {
+		case <-req.Context().Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send the request to each of endpoints in turn until one succeeds
+func (t *failoverTransport) tryEndpoints(req *http.Request, body []byte, endpoints []*Endpoint) (*http.Response, error) {
+	var lastErr error
+	for i, e := range endpoints {
+		u, err := url.Parse(e.Url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		err = e.limiter.Wait(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		r := req.WithContext(req.Context())
+		r.URL = u
+		r.Host = ""
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(r)
+		if err == nil {
+			resp, err = checkResponse(resp)
+		}
+		e.record(time.Since(start), err)
+		if err == nil {
+			if i != 0 {
+				logger.With(logger.Fields{"chain": t.chain, "endpoint": e.host()}).Debug("failed over to endpoint %d", i+1)
+			}
+			return resp, nil
+		}
+		lastErr = err
+
+		// the request was cancelled or timed out, so there is no point trying other endpoints
+		if req.Context().Err() != nil {
+			break
+		}
+		logger.With(logger.Fields{"chain": t.chain, "endpoint": e.host()}).Warn("rpc request failed, trying the next endpoint: %s", err)
+	}
+	return nil, lastErr
+}
+
+// the json-rpc error code hosted providers return when rate limiting, eg. infura
+const rateLimitedCode = -32005
+
+// an error for a response that is not 200 OK, or that is a rate limiting json-rpc error
+func checkResponse(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError(resp)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	msg := rpcResponse{}
+	if json.Unmarshal(body, &msg) == nil && msg.Error != nil && msg.Error.Code == rateLimitedCode {
+		return nil, errRateLimited
+	}
+	return resp, nil
+}
+
+// checkHeads asks every endpoint for its latest block every healthCheckInterval
+func (t *failoverTransport) checkHeads() {
+	for {
+		for _, e := range t.endpoints {
+			start := time.Now()
+			head, err := t.blockNumber(e)
+			e.record(time.Since(start), err)
+			if err != nil {
+				logger.With(logger.Fields{"chain": t.chain, "endpoint": e.host()}).Debug("health check failed: %s", err)
+				continue
+			}
+			e.setHead(head)
+		}
+		t.ranked()
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+func (t *failoverTransport) blockNumber(e *Endpoint) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := e.limiter.Wait(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rpc := NewRpcClient(e.Url, &http.Client{Transport: t.next})
+	return rpc.BlockNumber(ctx)
+}
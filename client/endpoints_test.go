@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFailover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer up.Close()
+
+	chain := &Chain{Name: "test", Endpoints: []*Endpoint{{Url: up.URL, Priority: 1}, {Url: down.URL}}}
+	transport := newFailoverTransport(chain, http.DefaultTransport)
+	client := &http.Client{Transport: transport}
+
+	// the preferred endpoint is down, so the request goes to the other one with the same body
+	resp, err := client.Post(down.URL, "application/json", bytes.NewReader([]byte(`{"id":1}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"id":1}` {
+		t.Fatalf("got %q from fallback endpoint, expected the request body back", body)
+	}
+
+	// after enough failures the endpoint that is down is tried last, despite its priority
+	for i := 0; i < 3; i++ {
+		client.Post(down.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+	}
+	if ranked := transport.ranked(); ranked[0].Url != up.URL {
+		t.Fatalf("got %s first, expected the healthy endpoint", ranked[0].Url)
+	}
+}
+
+func TestRankLagging(t *testing.T) {
+	preferred := &Endpoint{Url: "http://a", Priority: 0}
+	fallback := &Endpoint{Url: "http://b", Priority: 1}
+	chain := &Chain{Name: "test", MaxLag: 5, Endpoints: []*Endpoint{preferred, fallback}}
+	transport := newFailoverTransport(chain, http.DefaultTransport)
+
+	preferred.setHead(100)
+	fallback.setHead(104)
+	if ranked := transport.ranked(); ranked[0] != preferred {
+		t.Fatalf("got %s first, expected the endpoint with the lowest priority", ranked[0].Url)
+	}
+
+	fallback.setHead(110)
+	if ranked := transport.ranked(); ranked[0] != fallback {
+		t.Fatalf("got %s first, expected the endpoint that is not lagging behind", ranked[0].Url)
+	}
+}
+
+func TestSendRawTransactionNotRetried(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	retries := 3
+	chain := &Chain{Name: "test", Endpoints: []*Endpoint{{Url: server.URL}, {Url: server.URL, Priority: 1}}, RpcPolicy: &RpcPolicy{Retries: &retries, RetryDelay: 1}}
+	client := &http.Client{Transport: newFailoverTransport(chain, http.DefaultTransport)}
+
+	// the node may have got the tx before timing out, so it is not sent again or to the other endpoint
+	_, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`)))
+	if err == nil || requests != 1 {
+		t.Fatalf("got %v after %d requests, expected an error after 1", err, requests)
+	}
+
+	// other calls are retried on every endpoint
+	requests = 0
+	client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)))
+	if requests != 8 {
+		t.Fatalf("got %d requests for eth_blockNumber, expected 8", requests)
+	}
+}
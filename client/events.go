@@ -0,0 +1,151 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Deposit(address _recipient, uint _value, uint _toChain)
+type DepositEvent struct {
+	Recipient   common.Address
+	Value       *big.Int
+	ToChain     *big.Int
+	TxHash      common.Hash
+	BlockNumber uint64
+}
+
+// Withdraw(address _recipient, uint _value, uint _fromChain, bytes32 _txHash)
+// DepositHash is the _txHash argument, ie. the hash of the deposit on the other chain
+type WithdrawEvent struct {
+	Recipient   common.Address
+	Value       *big.Int
+	FromChain   *big.Int
+	DepositHash common.Hash
+	TxHash      common.Hash
+	BlockNumber uint64
+}
+
+// SignedForWithdraw(bytes32 _txHash, address _authority)
+type SignedEvent struct {
+	DepositHash common.Hash
+	Authority   common.Address
+	TxHash      common.Hash
+	BlockNumber uint64
+}
+
+var errShortLogData = errors.New("log data too short for event")
+
+// returns the i-th 32 byte word of abi encoded log data
+// none of the bridge event arguments are indexed, so they are all found in log.Data
+func word(data []byte, i int) []byte {
+	return data[i*32 : (i+1)*32]
+}
+
+func parseDeposit(log types.Log) (*DepositEvent, error) {
+	if len(log.Data) < 96 {
+		return nil, errShortLogData
+	}
+	return &DepositEvent{
+		Recipient:   common.BytesToAddress(word(log.Data, 0)),
+		Value:       new(big.Int).SetBytes(word(log.Data, 1)),
+		ToChain:     new(big.Int).SetBytes(word(log.Data, 2)),
+		TxHash:      log.TxHash,
+		BlockNumber: log.BlockNumber,
+	}, nil
+}
+
+func parseWithdraw(log types.Log) (*WithdrawEvent, error) {
+	if len(log.Data) < 128 {
+		return nil, errShortLogData
+	}
+	return &WithdrawEvent{
+		Recipient:   common.BytesToAddress(word(log.Data, 0)),
+		Value:       new(big.Int).SetBytes(word(log.Data, 1)),
+		FromChain:   new(big.Int).SetBytes(word(log.Data, 2)),
+		DepositHash: common.BytesToHash(word(log.Data, 3)),
+		TxHash:      log.TxHash,
+		BlockNumber: log.BlockNumber,
+	}, nil
+}
+
+func parseSigned(log types.Log) (*SignedEvent, error) {
+	if len(log.Data) < 64 {
+		return nil, errShortLogData
+	}
+	return &SignedEvent{
+		DepositHash: common.BytesToHash(word(log.Data, 0)),
+		Authority:   common.BytesToAddress(word(log.Data, 1)),
+		TxHash:      log.TxHash,
+		BlockNumber: log.BlockNumber,
+	}, nil
+}
+
+// ContractCreation(address _owner), BridgeFunded(address _addr), AuthorityAdded(address _addr),
+// AuthorityRemoved(address _addr)
+type AddressEvent struct {
+	Addr   common.Address
+	TxHash common.Hash
+}
+
+// Paid(address _addr, uint _value)
+type PaidEvent struct {
+	Addr   common.Address
+	Value  *big.Int
+	TxHash common.Hash
+}
+
+// ThresholdUpdated(uint256 _threshold)
+type ThresholdEvent struct {
+	Threshold *big.Int
+	TxHash    common.Hash
+}
+
+func parseAddressEvent(log types.Log) (*AddressEvent, error) {
+	if len(log.Data) < 32 {
+		return nil, errShortLogData
+	}
+	return &AddressEvent{
+		Addr:   common.BytesToAddress(word(log.Data, 0)),
+		TxHash: log.TxHash,
+	}, nil
+}
+
+func parsePaid(log types.Log) (*PaidEvent, error) {
+	if len(log.Data) < 64 {
+		return nil, errShortLogData
+	}
+	return &PaidEvent{
+		Addr:   common.BytesToAddress(word(log.Data, 0)),
+		Value:  new(big.Int).SetBytes(word(log.Data, 1)),
+		TxHash: log.TxHash,
+	}, nil
+}
+
+func parseThreshold(log types.Log) (*ThresholdEvent, error) {
+	if len(log.Data) < 32 {
+		return nil, errShortLogData
+	}
+	return &ThresholdEvent{
+		Threshold: new(big.Int).SetBytes(word(log.Data, 0)),
+		TxHash:    log.TxHash,
+	}, nil
+}
+
+// Withdraw(uint256 _amount) of the Foreign contract, emitted when the owner withdraws deposited ether
+type AmountEvent struct {
+	Amount *big.Int
+	TxHash common.Hash
+}
+
+func parseAmount(log types.Log) (*AmountEvent, error) {
+	if len(log.Data) < 32 {
+		return nil, errShortLogData
+	}
+	return &AmountEvent{
+		Amount: new(big.Int).SetBytes(word(log.Data, 0)),
+		TxHash: log.TxHash,
+	}, nil
+}
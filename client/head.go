@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// how old a cached head can be before it is asked for again, for callers other than the listener,
+// which refreshes it every time it polls
+const headMaxAge = 5 * time.Second
+
+// the latest block of a chain, shared by its listener and the trackers of txs sent on it
+type cachedHead struct {
+	number  *big.Int
+	updated time.Time
+}
+
+var headsMu sync.Mutex
+var heads = map[string]*cachedHead{}
+
+// chainHead returns the latest block number of chain, asking the node only if the cached head
+// is older than maxAge; a maxAge of 0 always asks
+func chainHead(ctx context.Context, chain *Chain, maxAge time.Duration) (*big.Int, error) {
+	headsMu.Lock()
+	cached, ok := heads[chain.Name]
+	headsMu.Unlock()
+	if ok && maxAge > 0 && time.Since(cached.updated) < maxAge {
+		return cached.number, nil
+	}
+
+	number, err := fetchHead(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	headsMu.Lock()
+	heads[chain.Name] = &cachedHead{number: number, updated: time.Now()}
+	headsMu.Unlock()
+	return number, nil
+}
+
+// fetchHead asks the node of chain for its latest block number, with eth_blockNumber over http
+// and the latest header otherwise, rather than fetching the whole block
+func fetchHead(ctx context.Context, chain *Chain) (*big.Int, error) {
+	if chain.Rpc != nil {
+		number, err := chain.Rpc.BlockNumber(ctx)
+		if err == nil {
+			return new(big.Int).SetUint64(number), nil
+		}
+		header, headerErr := chain.Client.HeaderByNumber(ctx, nil)
+		if headerErr != nil {
+			return nil, fmt.Errorf("eth_blockNumber: %s; eth_getBlockByNumber: %s", err, headerErr)
+		}
+		return header.Number, nil
+	}
+
+	header, err := chain.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return header.Number, nil
+}
+
+// newBlockGate returns a Waiter gate that is open only once there is a new block on chain since it was
+// last open, since nothing a tx does can change without one. it is open if the head cannot be found
+func newBlockGate(chain *Chain) func(ctx context.Context) bool {
+	var last *big.Int
+	return func(ctx context.Context) bool {
+		head, err := chainHead(ctx, chain, headMaxAge)
+		if err != nil {
+			return true
+		}
+		if last != nil && head.Cmp(last) == 0 {
+			return false
+		}
+		last = head
+		return true
+	}
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// how long a balance read from the chain is used before it is read again
+const balanceMaxAge = 15 * time.Second
+
+// Liquidity tracks the ether held by the contract that pays out withdraws on a chain against the
+// withdraws it still has to pay. withdraws the contract cannot cover are deferred instead of sent,
+// since the transfer to the recipient would revert, and are sent once the contract is funded.
+type Liquidity struct {
+	chain *Chain
+
+	mu        sync.Mutex
+	balance   *big.Int
+	updated   time.Time
+	pending   map[common.Hash]*big.Int // withdraws sent but not executed yet, by deposit tx hash
+	deferred  []*Withdrawal            // withdraws not sent for lack of funds, oldest first
+	level     string                   // level of the last alert sent, or AlertResolved
+	path      string                   // file deferred withdraws are saved to; not saved if empty
+	balanceAt func() (*big.Int, error)
+}
+
+var monitorsMu sync.Mutex
+var monitors = map[string]*Liquidity{}
+
+// LiquidityOf returns the liquidity monitor of chain, creating it and loading the withdraws
+// deferred on a previous run if it does not exist yet
+func LiquidityOf(chain *Chain) *Liquidity {
+	monitorsMu.Lock()
+	defer monitorsMu.Unlock()
+
+	l, ok := monitors[chain.Name]
+	if !ok {
+		l = newLiquidity(chain, "log/"+chain.Id.String()+"_deferred.json")
+		l.load()
+		monitors[chain.Name] = l
+	}
+	return l
+}
+
+func newLiquidity(chain *Chain, path string) *Liquidity {
+	return &Liquidity{
+		chain:   chain,
+		pending: make(map[common.Hash]*big.Int),
+		level:   AlertResolved,
+		path:    path,
+		balanceAt: func() (*big.Int, error) {
+			return chain.Client.BalanceAt(context.Background(), *chain.Contract, nil)
+		},
+	}
+}
+
+// only the bridge and home contracts pay out ether; the wrapped token mints instead
+func paysOut(chain *Chain) bool {
+	return chain.ContractType == BridgeContract || chain.ContractType == HomeContract || chain.ContractType == ""
+}
+
+// read the balance of the contract if the last one read is too old; l.mu must be held
+func (l *Liquidity) refresh(force bool) error {
+	if !force && l.balance != nil && time.Since(l.updated) < balanceMaxAge {
+		return nil
+	}
+	balance, err := l.balanceAt()
+	if err != nil {
+		return err
+	}
+	l.balance = balance
+	l.updated = time.Now()
+	return nil
+}
+
+// the balance minus the withdraws that have been sent but not executed; l.mu must be held
+func (l *Liquidity) available() *big.Int {
+	available := new(big.Int).Set(l.balance)
+	for _, value := range l.pending {
+		available.Sub(available, value)
+	}
+	return available
+}
+
+// Reserve returns true and counts value as pending if the contract can pay a withdraw of value
+// for the deposit with hash depositHash, on top of the withdraws already pending.
+// a deposit that is already pending, eg. when it is replayed, is not counted twice.
+func (l *Liquidity) Reserve(depositHash common.Hash, value *big.Int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.pending[depositHash]; ok {
+		return true
+	}
+
+	err := l.refresh(false)
+	if err != nil {
+		// relay anyway rather than hold up every deposit because a balance could not be read
+		logger.Warn("could not get balance of contract on %s: %s", l.chain.Name, err)
+		l.pending[depositHash] = value
+		return true
+	}
+
+	// withdraws deferred earlier go first
+	if len(l.deferred) != 0 || l.available().Cmp(value) < 0 {
+		return false
+	}
+	l.pending[depositHash] = value
+	return true
+}
+
+// Release stops counting the withdraw for depositHash as pending, once it has been executed or failed to send
+func (l *Liquidity) Release(depositHash common.Hash) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.pending[depositHash]; ok {
+		delete(l.pending, depositHash)
+		// the payout has left the contract
+		l.updated = time.Time{}
+	}
+}
+
+// Defer queues a withdraw that could not be covered, to be sent by Check once the contract is funded
+func (l *Liquidity) Defer(w *Withdrawal) {
+	l.mu.Lock()
+	for _, d := range l.deferred {
+		if d.TxHash == w.TxHash {
+			l.mu.Unlock()
+			return
+		}
+	}
+	l.deferred = append(l.deferred, w)
+	required := l.required()
+	balance := l.balance
+	l.level = AlertCritical
+	l.save()
+	deferredWithdrawals.Set(float64(len(l.deferred)), l.chain.Name)
+	l.mu.Unlock()
+
+	// every deferred withdraw is reported, not only the first
+	notify(AlertCritical, l.chain, "deferred withdraw of %s wei for deposit 0x%s: contract holds %s wei, %s wei needed for pending and deferred withdraws",
+		w.Value, w.TxHash, balance, required)
+}
+
+// total value of the pending and deferred withdraws; l.mu must be held
+func (l *Liquidity) required() *big.Int {
+	required := big.NewInt(0)
+	for _, value := range l.pending {
+		required.Add(required, value)
+	}
+	for _, w := range l.deferred {
+		required.Add(required, w.Value)
+	}
+	return required
+}
+
+// Deferred returns the number and total value of the deferred withdraws
+func (l *Liquidity) Deferred() (int, *big.Int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	total := big.NewInt(0)
+	for _, w := range l.deferred {
+		total.Add(total, w.Value)
+	}
+	return len(l.deferred), total
+}
+
+// Check reads the balance of the contract, sends an alert if it is below the chain's minBalance
+// or cannot cover the withdraws owed, and sends the deferred withdraws it can now cover, oldest first
+func (l *Liquidity) Check() {
+	l.mu.Lock()
+	err := l.refresh(true)
+	if err != nil {
+		l.mu.Unlock()
+		logger.Error("could not get balance of contract on %s: %s", l.chain.Name, err)
+		return
+	}
+	balance := new(big.Int).Set(l.balance)
+	l.mu.Unlock()
+
+	for {
+		w := l.nextDeferred()
+		if w == nil {
+			break
+		}
+		logger.Info("sending deferred withdraw of %s wei for deposit 0x%s on %s", w.Value, w.TxHash, l.chain.Name)
+		err = Withdraw(l.chain, w)
+		if err != nil && isTransient(err) {
+			// eg. the node is unreachable; the contract is still funded, so try again on the next check
+			logger.Warn("could not send deferred withdraw on %s, retrying on the next check: %s", l.chain.Name, err)
+			l.requeue(w)
+			break
+		}
+		if err != nil {
+			logger.Error("could not send deferred withdraw on %s: %s", l.chain.Name, err)
+			l.Release(common.HexToHash(w.TxHash))
+			store.SetStatus(common.HexToHash(w.TxHash), l.chain.Name, DepositFailed, err)
+			continue
+		}
+		store.SetStatus(common.HexToHash(w.TxHash), l.chain.Name, DepositRelayed, nil)
+	}
+
+	l.mu.Lock()
+	required := l.required()
+	deferred := len(l.deferred)
+	l.mu.Unlock()
+
+	contractBalance.Set(bigFloat(balance), l.chain.Name)
+	deferredWithdrawals.Set(float64(deferred), l.chain.Name)
+
+	switch {
+	case deferred != 0:
+		l.alert(AlertCritical, "%d withdraws deferred: contract holds %s wei, %s wei needed for pending and deferred withdraws", deferred, balance, required)
+	case l.chain.MinBalance != nil && balance.Cmp(l.chain.MinBalance) < 0:
+		l.alert(AlertWarning, "contract balance %s wei is below the minimum of %s wei", balance, l.chain.MinBalance)
+	default:
+		l.alert(AlertResolved, "contract balance is %s wei", balance)
+	}
+}
+
+// pop the oldest deferred withdraw if the contract can now cover it, counting it as pending
+func (l *Liquidity) nextDeferred() *Withdrawal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.deferred) == 0 {
+		return nil
+	}
+	w := l.deferred[0]
+	if l.available().Cmp(w.Value) < 0 {
+		return nil
+	}
+
+	l.deferred = l.deferred[1:]
+	l.pending[common.HexToHash(w.TxHash)] = w.Value
+	l.save()
+	return w
+}
+
+// put back a withdraw taken by nextDeferred that could not be sent, at the front so it is still sent first
+func (l *Liquidity) requeue(w *Withdrawal) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.pending, common.HexToHash(w.TxHash))
+	l.deferred = append([]*Withdrawal{w}, l.deferred...)
+	l.save()
+}
+
+// send an alert if its level differs from the last one sent, so a low balance is not reported every check
+func (l *Liquidity) alert(level string, format string, a ...interface{}) {
+	l.mu.Lock()
+	changed := level != l.level
+	l.level = level
+	l.mu.Unlock()
+
+	if changed {
+		notify(level, l.chain, format, a...)
+	}
+}
+
+// write the deferred withdraws to l.path; l.mu must be held
+func (l *Liquidity) save() {
+	if l.path == "" {
+		return
+	}
+	data, err := json.Marshal(l.deferred)
+	if err != nil {
+		logger.Error("could not save deferred withdraws on %s: %s", l.chain.Name, err)
+		return
+	}
+	err = ioutil.WriteFile(l.path+".tmp", data, 0644)
+	if err == nil {
+		err = os.Rename(l.path+".tmp", l.path)
+	}
+	if err != nil {
+		logger.Error("could not save deferred withdraws on %s: %s", l.chain.Name, err)
+	}
+}
+
+// read the withdraws deferred on a previous run from l.path
+func (l *Liquidity) load() {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("could not read deferred withdraws on %s: %s", l.chain.Name, err)
+		}
+		return
+	}
+	err = json.Unmarshal(data, &l.deferred)
+	if err != nil {
+		logger.Warn("invalid deferred withdraws in %s: %s", l.path, err)
+		return
+	}
+	if len(l.deferred) != 0 {
+		logger.Info("loaded %d deferred withdraws on %s", len(l.deferred), l.chain.Name)
+	}
+}
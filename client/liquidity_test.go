@@ -0,0 +1,102 @@
+package client
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type recordNotifier struct {
+	alerts []*Alert
+}
+
+func (r *recordNotifier) Notify(alert *Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestLiquidityReserve(t *testing.T) {
+	recorder := &recordNotifier{}
+	SetNotifier(recorder)
+	defer SetNotifier(LogNotifier{})
+
+	chain := &Chain{Name: "test", Id: big.NewInt(1)}
+	l := newLiquidity(chain, "")
+	l.balanceAt = func() (*big.Int, error) { return big.NewInt(100), nil }
+
+	a, b, c := common.HexToHash("0xa"), common.HexToHash("0xb"), common.HexToHash("0xc")
+
+	if !l.Reserve(a, big.NewInt(60)) {
+		t.Fatal("could not reserve 60 of 100")
+	}
+	// replaying a pending deposit does not count it twice
+	if !l.Reserve(a, big.NewInt(60)) {
+		t.Fatal("could not reserve pending deposit again")
+	}
+	if l.Reserve(b, big.NewInt(50)) {
+		t.Fatal("reserved 50 with only 40 available")
+	}
+
+	l.Defer(&Withdrawal{Value: big.NewInt(50), TxHash: b.Hex()[2:]})
+	l.Defer(&Withdrawal{Value: big.NewInt(50), TxHash: b.Hex()[2:]})
+	if n, total := l.Deferred(); n != 1 || total.Cmp(big.NewInt(50)) != 0 {
+		t.Fatalf("got %d deferred withdraws of %s, expected 1 of 50", n, total)
+	}
+	if len(recorder.alerts) != 1 || recorder.alerts[0].Level != AlertCritical {
+		t.Fatalf("expected one critical alert, got %v", recorder.alerts)
+	}
+
+	// deferred withdraws go first, even if a smaller one would fit
+	if l.Reserve(c, big.NewInt(10)) {
+		t.Fatal("reserved ahead of a deferred withdraw")
+	}
+
+	l.Release(a)
+	w := l.nextDeferred()
+	if w == nil || w.TxHash != b.Hex()[2:] {
+		t.Fatalf("expected deferred withdraw for %s once funds are available, got %v", b.Hex(), w)
+	}
+	if n, _ := l.Deferred(); n != 0 {
+		t.Fatalf("got %d deferred withdraws, expected 0", n)
+	}
+	if !l.Reserve(c, big.NewInt(10)) {
+		t.Fatal("could not reserve 10 with 50 available")
+	}
+}
+
+func TestDeferredWithdrawRetried(t *testing.T) {
+	defer func() { store = &Store{size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)} }()
+
+	// the node is down, so the withdraw cannot be sent for now
+	chain, closeNode := testChain(t, "deferred-retried", 1, newBridgeNode(50))
+	closeNode()
+	l := newLiquidity(chain, "")
+	l.balanceAt = func() (*big.Int, error) { return big.NewInt(100), nil }
+	deposit := common.HexToHash("0xd1")
+	origin := &Chain{Name: "deferred-origin", Id: big.NewInt(2)}
+	event := &DepositEvent{TxHash: deposit, Recipient: *chain.From, Value: big.NewInt(50), ToChain: chain.Id}
+	store.Seen(origin, event)
+	store.SetStatus(deposit, chain.Name, DepositDeferred, nil)
+	l.Defer(newWithdrawal(origin, event))
+
+	l.Check()
+	if n, _ := l.Deferred(); n != 1 {
+		t.Fatalf("got %d deferred withdraws after the node failed, expected it to be kept", n)
+	}
+	if r := store.Get(deposit); r.Status != DepositDeferred {
+		t.Fatalf("got status %s after the node failed, expected %s", r.Status, DepositDeferred)
+	}
+
+	// the node is back, but the chain has no signer, which will not get better by trying again
+	chain, closeNode = testChain(t, "deferred-retried", 1, newBridgeNode(50))
+	defer closeNode()
+	l.chain = chain
+	l.Check()
+	if n, _ := l.Deferred(); n != 0 {
+		t.Fatalf("got %d deferred withdraws, expected the failed one to be dropped", n)
+	}
+	if r := store.Get(deposit); r.Status != DepositFailed {
+		t.Fatalf("got status %s, expected %s", r.Status, DepositFailed)
+	}
+}
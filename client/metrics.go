@@ -0,0 +1,173 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// kinds of metric, as written in the # TYPE line
+const (
+	counterMetric = "counter"
+	gaugeMetric   = "gauge"
+	summaryMetric = "summary" // only the _sum and _count of observations, no quantiles
+)
+
+// a metric with one value per combination of label values, written in the prometheus text format
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu      sync.Mutex
+	samples map[string]*sample
+}
+
+type sample struct {
+	labels []string
+	value  float64 // value of a counter or gauge, or sum of observations of a summary
+	count  uint64  // number of observations of a summary
+}
+
+var registry []*metric
+
+func newMetric(name, help, kind string, labels ...string) *metric {
+	m := &metric{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		samples: make(map[string]*sample),
+	}
+	registry = append(registry, m)
+	return m
+}
+
+// metrics of the relayer, all labelled by chain name
+var (
+	latestBlock         = newMetric("chainbridge_latest_block", "Latest block number seen on the chain.", gaugeMetric, "chain")
+	checkpointBlock     = newMetric("chainbridge_checkpoint_block", "Last block fully scanned for logs.", gaugeMetric, "chain")
+	blockLag            = newMetric("chainbridge_block_lag", "Number of blocks between the latest block and the checkpoint.", gaugeMetric, "chain")
+	depositsSeen        = newMetric("chainbridge_deposits_seen_total", "Deposits found on the chain.", counterMetric, "chain")
+	withdrawsSubmitted  = newMetric("chainbridge_withdrawals_submitted_total", "Withdraw and mint txs sent to the chain.", counterMetric, "chain")
+	withdrawsConfirmed  = newMetric("chainbridge_withdrawals_confirmed_total", "Withdraw and mint txs mined successfully.", counterMetric, "chain")
+	withdrawsFailed     = newMetric("chainbridge_withdrawals_failed_total", "Withdraw and mint txs that could not be sent, reverted or were never mined.", counterMetric, "chain")
+	rpcErrors           = newMetric("chainbridge_rpc_errors_total", "Failed rpc calls to the node.", counterMetric, "chain", "method")
+	rpcEndpointUp       = newMetric("chainbridge_rpc_endpoint_up", "1 if the rpc endpoint is healthy, 0 if it is failing or lagging behind.", gaugeMetric, "chain", "endpoint")
+	rpcRetries          = newMetric("chainbridge_rpc_retries_total", "Rpc requests retried after a transient error.", counterMetric, "chain")
+	rpcDuration         = newMetric("chainbridge_rpc_duration_seconds", "Duration of rpc calls to the node.", summaryMetric, "chain", "method")
+	gasSpent            = newMetric("chainbridge_gas_spent_wei_total", "Wei spent on gas by the relayer account.", counterMetric, "chain")
+	relayerBalance      = newMetric("chainbridge_relayer_balance_wei", "Balance of the relayer account.", gaugeMetric, "chain")
+	relayerWithdraws    = newMetric("chainbridge_relayer_affordable_withdrawals", "Number of withdraws the relayer account can pay gas for.", gaugeMetric, "chain")
+	relayerState        = newMetric("chainbridge_relayer_state", "1 for the current state of the relayer account: ok, warning or critical.", gaugeMetric, "chain", "state")
+	contractBalance     = newMetric("chainbridge_contract_balance_wei", "Balance of the bridge contract.", gaugeMetric, "chain")
+	deferredWithdrawals = newMetric("chainbridge_deferred_withdrawals", "Withdraws deferred because the contract cannot cover them.", gaugeMetric, "chain")
+	relayQueueLength    = newMetric("chainbridge_relay_queue_length", "Deposits waiting to be relayed to the chain.", gaugeMetric, "chain")
+)
+
+func (m *metric) sample(labels []string) *sample {
+	key := strings.Join(labels, "\xff")
+	s, ok := m.samples[key]
+	if !ok {
+		s = &sample{labels: labels}
+		m.samples[key] = s
+	}
+	return s
+}
+
+func (m *metric) Set(value float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sample(labels).value = value
+}
+
+func (m *metric) Add(value float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sample(labels).value += value
+}
+
+func (m *metric) Inc(labels ...string) {
+	m.Add(1, labels...)
+}
+
+func (m *metric) Observe(value float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sample(labels)
+	s.value += value
+	s.count++
+}
+
+// big ints such as balances in wei are exported as floats, like every prometheus value
+func bigFloat(n *big.Int) float64 {
+	if n == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return f
+}
+
+func escapeLabel(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return strings.Replace(value, "\n", `\n`, -1)
+}
+
+func (m *metric) labelString(s *sample) string {
+	if len(m.labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(m.labels))
+	for i, label := range m.labels {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", label, escapeLabel(s.labels[i]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (m *metric) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]string, 0, len(m.samples))
+	for key := range m.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := m.samples[key]
+		labels := m.labelString(s)
+		if m.kind == summaryMetric {
+			fmt.Fprintf(w, "%s_sum%s %g\n", m.name, labels, s.value)
+			fmt.Fprintf(w, "%s_count%s %d\n", m.name, labels, s.count)
+		} else {
+			fmt.Fprintf(w, "%s%s %g\n", m.name, labels, s.value)
+		}
+	}
+}
+
+// WriteMetrics writes every metric in the prometheus text format
+func WriteMetrics(w io.Writer) {
+	for _, m := range registry {
+		m.write(w)
+	}
+}
+
+// ServeMetrics serves the metrics at http://addr/metrics for prometheus to scrape
+func ServeMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WriteMetrics(w)
+	})
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	counter := &metric{name: "test_total", help: "A test counter.", kind: counterMetric, labels: []string{"chain"}, samples: make(map[string]*sample)}
+	counter.Inc("kovan")
+	counter.Add(2, "kovan")
+	counter.Inc(`a "quoted" chain`)
+
+	summary := &metric{name: "test_seconds", help: "A test summary.", kind: summaryMetric, labels: []string{"chain", "method"}, samples: make(map[string]*sample)}
+	summary.Observe(0.5, "kovan", "eth_getLogs")
+	summary.Observe(1.5, "kovan", "eth_getLogs")
+
+	var out bytes.Buffer
+	counter.write(&out)
+	summary.write(&out)
+
+	expected := []string{
+		"# TYPE test_total counter",
+		`test_total{chain="kovan"} 3`,
+		`test_total{chain="a \"quoted\" chain"} 1`,
+		"# TYPE test_seconds summary",
+		`test_seconds_sum{chain="kovan",method="eth_getLogs"} 2`,
+		`test_seconds_count{chain="kovan",method="eth_getLogs"} 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRpcMethod(t *testing.T) {
+	cases := map[string]string{
+		`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`: "eth_blockNumber",
+		`[{"jsonrpc":"2.0","method":"eth_getBalance","id":1}]`:            "batch",
+		`not json`: "unknown",
+	}
+	for body, expected := range cases {
+		if actual := rpcMethod([]byte(body)); actual != expected {
+			t.Errorf("%s -- got: %s expected: %s", body, actual, expected)
+		}
+	}
+
+	if rpcFailed([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)) {
+		t.Error("successful response counted as failed")
+	}
+	if !rpcFailed([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"oops"}}`)) {
+		t.Error("error response not counted as failed")
+	}
+	if !rpcFailed([]byte(`[{"id":1,"result":"0x1"},{"id":2,"error":{"code":-32000}}]`)) {
+		t.Error("batch with an error not counted as failed")
+	}
+}
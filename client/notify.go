@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// levels of an alert
+const (
+	AlertResolved = "resolved" // a previous alert no longer applies
+	AlertWarning  = "warning"  // needs attention soon, eg. balance below minBalance
+	AlertCritical = "critical" // relaying is held up, eg. withdraws deferred for lack of funds
+)
+
+type Alert struct {
+	Level   string    `json:"level"`
+	Chain   string    `json:"chain"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier sends alerts to wherever an operator will see them
+type Notifier interface {
+	Notify(alert *Alert) error
+}
+
+// notifier alerts are sent to; alerts are logged if none is set
+var notifier Notifier = LogNotifier{}
+
+func SetNotifier(n Notifier) {
+	notifier = n
+}
+
+// send an alert through the notifier
+func notify(level string, chain *Chain, format string, a ...interface{}) {
+	alert := &Alert{
+		Level:   level,
+		Chain:   chain.Name,
+		Message: fmt.Sprintf(format, a...),
+		Time:    time.Now(),
+	}
+	err := notifier.Notify(alert)
+	if err != nil {
+		logger.Error("could not send %s alert for %s: %s: %s", alert.Level, alert.Chain, alert.Message, err)
+	}
+}
+
+// LogNotifier prints alerts to the log
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(alert *Alert) error {
+	switch alert.Level {
+	case AlertCritical:
+		logger.Error("alert: %s: %s", alert.Chain, alert.Message)
+	case AlertWarning:
+		logger.Warn("alert: %s: %s", alert.Chain, alert.Message)
+	default:
+		logger.Info("alert %s: %s: %s", alert.Level, alert.Chain, alert.Message)
+	}
+	return nil
+}
+
+// WebhookNotifier posts alerts as json to a url, eg. a slack or discord incoming webhook
+type WebhookNotifier struct {
+	Url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		Url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Notify(alert *Alert) error {
+	body, err := json.Marshal(struct {
+		*Alert
+		Text string `json:"text"` // shown by slack and discord
+	}{alert, fmt.Sprintf("[%s] %s: %s", alert.Level, alert.Chain, alert.Message)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.Url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// MultiNotifier sends every alert to each of its notifiers
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(alert *Alert) error {
+	var failed error
+	for _, n := range m {
+		err := n.Notify(alert)
+		if err != nil {
+			failed = err
+		}
+	}
+	return failed
+}
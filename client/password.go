@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// environment variables holding keystore passwords; the password of a single account is in
+// CHAINBRIDGE_PASSWORD_<ADDRESS>, the address in upper case hex without 0x
+const (
+	PasswordEnv        = "CHAINBRIDGE_PASSWORD"
+	accountPasswordEnv = PasswordEnv + "_"
+)
+
+// PasswordSource finds the password of each account in the keystore. for an account, it looks in turn at
+//
+//	Env, the environment variable of the chain the account is for, if set
+//	CHAINBRIDGE_PASSWORD_<ADDRESS>
+//	a line "0x<address>=<password>" in File
+//	the first other line of File, the password of every account not listed
+//	CHAINBRIDGE_PASSWORD
+//	Flag, the --password flag
+//
+// and then prompts for it if Prompt is set and stdin is a terminal
+type PasswordSource struct {
+	File   string
+	Env    string
+	Flag   string
+	Prompt bool
+
+	once     sync.Once
+	fileErr  error
+	shared   *string                   // the password of accounts not listed in File
+	accounts map[common.Address]string // listed in File, and any prompted for
+}
+
+// Password returns the password of address, or an error saying where it can be given
+func (p *PasswordSource) Password(address common.Address) (string, error) {
+	p.once.Do(p.readFile)
+	if p.fileErr != nil {
+		return "", p.fileErr
+	}
+
+	if p.Env != "" {
+		if password, ok := os.LookupEnv(p.Env); ok {
+			return password, nil
+		}
+	}
+	if password, ok := os.LookupEnv(accountPasswordEnv + strings.ToUpper(address.Hex()[2:])); ok {
+		return password, nil
+	}
+	if password, ok := p.accounts[address]; ok {
+		return password, nil
+	}
+	if p.shared != nil {
+		return *p.shared, nil
+	}
+	if password, ok := os.LookupEnv(PasswordEnv); ok {
+		return password, nil
+	}
+	if p.Flag != "" {
+		return p.Flag, nil
+	}
+
+	if p.Prompt && isTerminal(os.Stdin) {
+		password, err := console.Stdin.PromptPassword(fmt.Sprintf("password of account %s: ", address.Hex()))
+		if err != nil {
+			return "", err
+		}
+		// so chains sharing an account do not prompt again
+		p.accounts[address] = password
+		return password, nil
+	}
+	env := PasswordEnv
+	if p.Env != "" {
+		env = p.Env
+	}
+	return "", fmt.Errorf("no password for account %s: set %s or %s%s, or use --password-file", address.Hex(), env, accountPasswordEnv, strings.ToUpper(address.Hex()[2:]))
+}
+
+// NewPassword returns the password to encrypt a new key with: the first line of File, CHAINBRIDGE_PASSWORD
+// or Flag, or else one prompted for twice
+func (p *PasswordSource) NewPassword() (string, error) {
+	p.once.Do(p.readFile)
+	if p.fileErr != nil {
+		return "", p.fileErr
+	}
+
+	if p.shared != nil {
+		return *p.shared, nil
+	}
+	if password, ok := os.LookupEnv(PasswordEnv); ok {
+		return password, nil
+	}
+	if p.Flag != "" {
+		return p.Flag, nil
+	}
+
+	if !p.Prompt || !isTerminal(os.Stdin) {
+		return "", fmt.Errorf("no password for the new key: set %s or use --password-file", PasswordEnv)
+	}
+	password, err := console.Stdin.PromptPassword("password to encrypt the key with: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := console.Stdin.PromptPassword("repeat password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return password, nil
+}
+
+// read the passwords in File, which only its owner may be able to read
+func (p *PasswordSource) readFile() {
+	p.accounts = map[common.Address]string{}
+	if p.File == "" {
+		return
+	}
+
+	f, err := os.Open(p.File)
+	if err != nil {
+		p.fileErr = fmt.Errorf("could not read password file: %s", err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		p.fileErr = fmt.Errorf("could not read password file: %s", err)
+		return
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		p.fileErr = fmt.Errorf("password file %s can be read by other users (mode %s); chmod 600 it", p.File, info.Mode().Perm())
+		return
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 42 && line[42] == '=' && common.IsHexAddress(line[:42]) {
+			p.accounts[common.HexToAddress(line[:42])] = line[43:]
+			continue
+		}
+		if p.shared == nil {
+			shared := line
+			p.shared = &shared
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		p.fileErr = fmt.Errorf("could not read password file: %s", err)
+	}
+}
+
+// whether f is a terminal someone can type a password into
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// UnlockAccount unlocks address in ks until the relayer exits, so its key is decrypted once
+// rather than for every tx
+func UnlockAccount(ks *keystore.KeyStore, keystorePath string, address common.Address, password string) error {
+	if !ks.HasAddress(address) {
+		return fmt.Errorf("account %s is not in the keystore at %s", address.Hex(), keystorePath)
+	}
+	err := ks.Unlock(accounts.Account{Address: address}, password)
+	if err != nil {
+		return fmt.Errorf("could not unlock account %s: %s", address.Hex(), err)
+	}
+	logger.Info("unlocked account %s", address.Hex())
+	return nil
+}
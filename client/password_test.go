@@ -0,0 +1,73 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPasswordSource(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "passwords")
+	defer os.RemoveAll(dir)
+
+	listed := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	file := filepath.Join(dir, "passwords")
+	ioutil.WriteFile(file, []byte(listed.Hex()+"=first=password\nshared password\n"), 0600)
+
+	p := &PasswordSource{File: file, Flag: "flag"}
+	if password, _ := p.Password(listed); password != "first=password" {
+		t.Fatalf("password of listed account: %q", password)
+	}
+	if password, _ := p.Password(other); password != "shared password" {
+		t.Fatalf("password of other account: %q", password)
+	}
+
+	env := accountPasswordEnv + strings.ToUpper(other.Hex()[2:])
+	os.Setenv(env, "from env")
+	defer os.Unsetenv(env)
+	if password, _ := p.Password(other); password != "from env" {
+		t.Fatalf("password from %s: %q", env, password)
+	}
+
+	if password, _ := (&PasswordSource{Flag: "flag"}).Password(listed); password != "flag" {
+		t.Fatalf("password from flag: %q", password)
+	}
+	if _, err := (&PasswordSource{}).Password(listed); err == nil {
+		t.Fatal("found a password where there is none")
+	}
+
+	// a file other users can read is refused
+	os.Chmod(file, 0644)
+	if _, err := (&PasswordSource{File: file}).Password(listed); err == nil {
+		t.Fatal("read a password file other users can read")
+	}
+}
+
+func TestUnlockAccount(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "keystore")
+	defer os.RemoveAll(dir)
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, _ := ks.NewAccount("secret")
+
+	if err := UnlockAccount(ks, dir, common.HexToAddress("0x1"), "secret"); err == nil || !strings.Contains(err.Error(), "not in the keystore") {
+		t.Fatalf("unlocked an account that is not in the keystore: %v", err)
+	}
+	if err := UnlockAccount(ks, dir, account.Address, "wrong"); err == nil {
+		t.Fatal("unlocked an account with the wrong password")
+	}
+	if err := UnlockAccount(ks, dir, account.Address, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// once unlocked, txs are signed without the password
+	_, err := NewUnlockedSigner(ks, account.Address).SignHash(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// number of deposits that can wait to be relayed to each chain before the listeners finding
+// more deposits for it have to wait
+const defaultQueueSize = 100
+
+var errPoolStopped = errors.New("relay pool stopped")
+
+// a deposit found on origin, waiting to be relayed
+type relayJob struct {
+	origin    *Chain
+	allChains []*Chain
+	deposit   *DepositEvent
+}
+
+// RelayPool relays deposits with one worker per destination chain, so the deposits to each chain
+// are relayed in the order they were found while different chains are relayed in parallel.
+// each worker has a bounded queue; once it is full, the listener that found the deposit waits.
+// deposits still queued when the pool is stopped stay in the store as seen, and are queued
+// again when their origin chain is listened to next.
+type RelayPool struct {
+	mu     sync.Mutex
+	size   int
+	queues map[string]chan *relayJob
+	done   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+	relay  func(job *relayJob) error
+}
+
+var pool = NewRelayPool(defaultQueueSize)
+
+func NewRelayPool(size int) *RelayPool {
+	if size < 1 {
+		size = 1
+	}
+	return &RelayPool{
+		size:   size,
+		queues: make(map[string]chan *relayJob),
+		done:   make(chan struct{}),
+		relay: func(job *relayJob) error {
+			return HandleDeposit(job.origin, job.allChains, job.deposit)
+		},
+	}
+}
+
+// StartRelayPool replaces the pool deposits are relayed with by one whose queues hold size deposits;
+// it must be called before any chain is listened to
+func StartRelayPool(size int) {
+	pool = NewRelayPool(size)
+}
+
+// StopRelayPool waits for the deposit each worker is relaying, and stops relaying the rest
+func StopRelayPool() {
+	pool.Stop()
+}
+
+// Enqueue queues a deposit found on origin to be relayed by the worker of its destination chain,
+// waiting if the queue is full until ctx is cancelled
+func (p *RelayPool) Enqueue(ctx context.Context, origin *Chain, allChains []*Chain, deposit *DepositEvent) error {
+	select {
+	case <-p.done:
+		return errPoolStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	dest := FindChain(deposit.ToChain, allChains)
+	if dest == nil {
+		// cannot be relayed, so there is nothing to wait for
+		return p.relay(&relayJob{origin, allChains, deposit})
+	}
+
+	job := &relayJob{origin, allChains, deposit}
+	q := p.queue(dest.Name)
+	select {
+	case q <- job:
+	default:
+		logger.With(logger.Fields{"chain": origin.Name, "dest": dest.Name}).Warn("relay queue to %s is full, waiting", dest.Name)
+		select {
+		case q <- job:
+		case <-p.done:
+			return errPoolStopped
+		case <-ctx.Done():
+			// the deposit stays in the store as seen, and is queued again on the next start
+			return ctx.Err()
+		}
+	}
+	relayQueueLength.Set(float64(len(q)), dest.Name)
+	return nil
+}
+
+// the queue of deposits to dest, starting its worker if there is not one yet
+func (p *RelayPool) queue(dest string) chan *relayJob {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	q, ok := p.queues[dest]
+	if !ok {
+		q = make(chan *relayJob, p.size)
+		p.queues[dest] = q
+		p.wg.Add(1)
+		go p.work(dest, q)
+	}
+	return q
+}
+
+func (p *RelayPool) work(dest string, q chan *relayJob) {
+	defer p.wg.Done()
+	for {
+		// stop as soon as the pool is stopped, even if there are deposits left
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		select {
+		case <-p.done:
+			return
+		case job := <-q:
+			relayQueueLength.Set(float64(len(q)), dest)
+			// the same deposit may have been queued twice, eg. by a rescan
+			if r := store.Get(job.deposit.TxHash); r != nil && (r.Status == DepositRelayed || r.Status == DepositExecuted) {
+				continue
+			}
+			err := p.relay(job)
+			if err != nil {
+				logger.With(logger.Fields{"chain": job.origin.Name, "deposit": job.deposit.TxHash.Hex(), "dest": dest}).Error("could not relay deposit: %s", err)
+			}
+		}
+	}
+}
+
+// Stop stops every worker once it has relayed the deposit it is on
+func (p *RelayPool) Stop() {
+	p.once.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+// queue the deposits found on chain before the last shutdown that were not relayed yet, until ctx is cancelled
+func requeueSeen(ctx context.Context, chain *Chain, allChains []*Chain) {
+	for _, r := range store.Unrelayed(chain.Name) {
+		logger.With(logger.Fields{"chain": chain.Name, "deposit": r.TxHash.Hex()}).Info("queueing deposit that was not relayed before the last shutdown")
+		err := pool.Enqueue(ctx, chain, allChains, r.event())
+		if err != nil {
+			return
+		}
+	}
+}
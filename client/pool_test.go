@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRelayPool(t *testing.T) {
+	origin := &Chain{Name: "origin", Id: big.NewInt(1)}
+	slow := &Chain{Name: "slow", Id: big.NewInt(2)}
+	fast := &Chain{Name: "fast", Id: big.NewInt(3)}
+	allChains := []*Chain{slow, fast}
+
+	var mu sync.Mutex
+	relayed := map[string][]common.Hash{}
+	release := make(chan struct{})
+	fastDone := make(chan struct{})
+
+	p := NewRelayPool(1)
+	p.relay = func(job *relayJob) error {
+		dest := FindChain(job.deposit.ToChain, job.allChains)
+		if dest == slow {
+			<-release
+		}
+		mu.Lock()
+		relayed[dest.Name] = append(relayed[dest.Name], job.deposit.TxHash)
+		mu.Unlock()
+		if dest == fast {
+			close(fastDone)
+		}
+		return nil
+	}
+
+	a, b, c := common.HexToHash("0xa"), common.HexToHash("0xb"), common.HexToHash("0xc")
+	p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: a, ToChain: slow.Id})
+	p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: b, ToChain: slow.Id})
+
+	// a deposit to another chain is not held up by the slow one
+	p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: c, ToChain: fast.Id})
+	select {
+	case <-fastDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deposit to fast chain was not relayed while the slow chain was busy")
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(relayed[slow.Name])
+		mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Stop()
+
+	// deposits to the same chain are relayed in order
+	if got := relayed[slow.Name]; len(got) != 2 || got[0] != a || got[1] != b {
+		t.Fatalf("relayed %v to slow chain, expected %v then %v", got, a.Hex(), b.Hex())
+	}
+
+	if err := p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: a, ToChain: slow.Id}); err != errPoolStopped {
+		t.Fatalf("got %v enqueueing to a stopped pool, expected %v", err, errPoolStopped)
+	}
+}
+
+func TestRelayPoolFullQueueCancelled(t *testing.T) {
+	origin := &Chain{Name: "origin", Id: big.NewInt(1)}
+	dest := &Chain{Name: "dest", Id: big.NewInt(2)}
+	allChains := []*Chain{dest}
+
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	p := NewRelayPool(1)
+	p.relay = func(job *relayJob) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	defer p.Stop()
+	defer close(release)
+
+	// one deposit being relayed and one filling the queue
+	p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: common.HexToHash("0xa"), ToChain: dest.Id})
+	<-started
+	p.Enqueue(context.Background(), origin, allChains, &DepositEvent{TxHash: common.HexToHash("0xb"), ToChain: dest.Id})
+
+	// a listener waiting for room stops waiting when it is shut down
+	ctx, cancel := context.WithCancel(context.Background())
+	enqueued := make(chan error, 1)
+	go func() {
+		enqueued <- p.Enqueue(ctx, origin, allChains, &DepositEvent{TxHash: common.HexToHash("0xc"), ToChain: dest.Id})
+	}()
+	select {
+	case err := <-enqueued:
+		t.Fatalf("got %v, expected to wait for room in the queue", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case err := <-enqueued:
+		if err != context.Canceled {
+			t.Fatalf("got %v after cancelling, expected %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("still waiting for room in the queue after cancelling")
+	}
+}
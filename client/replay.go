@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+type ReplayOptions struct {
+	From      *big.Int
+	To        *big.Int // if nil, replay up to the latest block
+	ChunkSize uint64   // maximum number of blocks to query at a time; if 0, the chain's maxBlockRange is used
+	Relay     bool     // re-relay deposits that were not withdrawn on their destination chain
+	DryRun    bool     // only print the withdrawals that would be sent
+}
+
+// create a withdrawal on the destination chain for a deposit made on chain
+func newWithdrawal(chain *Chain, deposit *DepositEvent) *Withdrawal {
+	return &Withdrawal{
+		Recipient: hex.EncodeToString(deposit.Recipient.Bytes()),
+		Value:     deposit.Value,
+		FromChain: padBigTo32Bytes(chain.Id),
+		TxHash:    deposit.TxHash.Hex()[2:],
+	}
+}
+
+// Replay scans the blocks opts.From to opts.To on chain in chunks of at most opts.ChunkSize blocks
+// and lists every bridge event found. if opts.Relay is set, deposits that have no corresponding
+// Withdraw on their destination chain are relayed again.
+func Replay(chain *Chain, allChains []*Chain, e *Events, ks *keystore.KeyStore, fl map[string]bool, opts *ReplayOptions) error {
+	events = e
+	keys = ks
+	flags = fl
+
+	to := opts.To
+	if to == nil {
+		head, err := chainHead(context.Background(), chain, headMaxAge)
+		if err != nil {
+			return err
+		}
+		to = head
+	}
+
+	routes := contractRoutes(chain)
+	relayed := 0
+	if deposit, ok := routes[common.HexToHash(depositEventId(chain))]; ok && opts.Relay {
+		printEvent := deposit.Handle
+		deposit.Handle = func(chain *Chain, log types.Log, event interface{}) error {
+			printEvent(chain, log, event)
+			if replayDeposit(chain, allChains, event.(*DepositEvent), opts.DryRun) {
+				relayed++
+			}
+			return nil
+		}
+	}
+	router := NewRouter()
+	router.RegisterAll(*chain.Contract, routes)
+
+	scanner := NewScanner(chain, router.Query())
+	if opts.ChunkSize != 0 {
+		scanner.size = opts.ChunkSize
+		scanner.max = opts.ChunkSize
+	}
+	logger.Info("replaying %s from block %s to %s", chain.Name, opts.From, to)
+
+	err := scanner.Scan(context.Background(), opts.From.Uint64(), to.Uint64(), func(logs []types.Log, start, end uint64) error {
+		if flags["v"] {
+			logger.Info("blocks %d to %d on %s: %d logs", start, end, chain.Name, len(logs))
+		}
+
+		for _, log := range logs {
+			// the route logs the event itself
+			if router.Route(log) == nil {
+				continue
+			}
+			_, err := router.Dispatch(chain, log)
+			if err != nil {
+				logger.Error("%s", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Relay && opts.DryRun {
+		logger.Info("%d deposits on %s would be relayed", relayed, chain.Name)
+	} else if opts.Relay {
+		logger.Info("relayed %d deposits on %s", relayed, chain.Name)
+	}
+	return nil
+}
+
+// relay a deposit again if it was not yet withdrawn and the relayer has not already signed for it.
+// returns whether it was relayed, or would have been in a dry run
+func replayDeposit(chain *Chain, allChains []*Chain, deposit *DepositEvent, dryRun bool) bool {
+	dest := FindChain(deposit.ToChain, allChains)
+	if dest == nil {
+		logger.Warn("deposit %s is to chain %s which is not in the config", deposit.TxHash.Hex(), deposit.ToChain)
+		return false
+	}
+
+	withdraw, signatures, err := findWithdraw(dest, deposit.TxHash)
+	if err != nil {
+		logger.Error("could not look up withdraw on %s: %s", dest.Name, err)
+		return false
+	}
+	if withdraw != nil {
+		logger.Info("deposit %s already withdrawn on %s in tx %s", deposit.TxHash.Hex(), dest.Name, withdraw.TxHash.Hex())
+		return false
+	}
+	for _, sig := range signatures {
+		if sig.Authority == *dest.From {
+			logger.Info("deposit %s already signed for by %s on %s", deposit.TxHash.Hex(), dest.From.Hex(), dest.Name)
+			return false
+		}
+	}
+
+	if dryRun {
+		logger.Info("would relay deposit %s: %s wei to %s on %s", deposit.TxHash.Hex(), deposit.Value, deposit.Recipient.Hex(), dest.Name)
+		return true
+	}
+
+	logger.Info("relaying deposit %s: %s wei to %s on %s", deposit.TxHash.Hex(), deposit.Value, deposit.Recipient.Hex(), dest.Name)
+	err = HandleDeposit(chain, allChains, deposit)
+	if err != nil {
+		logger.Error("could not relay deposit %s: %s", deposit.TxHash.Hex(), err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestReplayDeposit(t *testing.T) {
+	events = testEvents
+	defer func() { store = &Store{size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)} }()
+	originNode, destNode := newBridgeNode(100), newBridgeNode(50)
+	origin, closeOrigin := testChain(t, "replay-origin", 1, originNode)
+	defer closeOrigin()
+	dest, closeDest := testChain(t, "replay-dest", 2, destNode)
+	defer closeDest()
+	allChains := []*Chain{origin, dest}
+
+	recipient := common.HexToAddress("0x83a8e0bd54ff6dc11da80151563b8150534280be")
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	withdrawn, signed, signedByOther, missed := common.HexToHash("0xd1"), common.HexToHash("0xd2"), common.HexToHash("0xd3"), common.HexToHash("0xd4")
+	destNode.update(func() {
+		destNode.logs = []types.Log{
+			withdrawLog(dest, common.HexToHash("0xe1"), 40, recipient, 10, origin.Id, withdrawn),
+			signedLog(dest, common.HexToHash("0xe2"), 41, signed, *dest.From),
+			signedLog(dest, common.HexToHash("0xe3"), 42, signedByOther, other),
+		}
+	})
+
+	deposit := func(hash common.Hash, toChain *big.Int) *DepositEvent {
+		return &DepositEvent{Recipient: recipient, Value: big.NewInt(10), ToChain: toChain, TxHash: hash, BlockNumber: 90}
+	}
+	tests := []struct {
+		name    string
+		deposit *DepositEvent
+		relay   bool
+	}{
+		{"withdrawn", deposit(withdrawn, dest.Id), false},
+		{"signed by the relayer", deposit(signed, dest.Id), false},
+		{"signed by another authority", deposit(signedByOther, dest.Id), true},
+		{"not relayed", deposit(missed, dest.Id), true},
+		{"to a chain not in the config", deposit(missed, big.NewInt(99)), false},
+	}
+	for _, test := range tests {
+		store.Seen(origin, test.deposit)
+		if relay := replayDeposit(origin, allChains, test.deposit, true); relay != test.relay {
+			t.Errorf("%s: got %t, expected %t", test.name, relay, test.relay)
+		}
+		// nothing is relayed in a dry run
+		if r := store.Get(test.deposit.TxHash); r.Status != DepositSeen {
+			t.Errorf("%s: got status %s after a dry run, expected %s", test.name, r.Status, DepositSeen)
+		}
+	}
+
+	// the whole range in a dry run
+	originNode.update(func() {
+		originNode.logs = []types.Log{
+			depositLog(origin, withdrawn, 90, recipient, 10, dest.Id),
+			depositLog(origin, missed, 91, recipient, 10, dest.Id),
+		}
+	})
+	err := Replay(origin, allChains, testEvents, nil, map[string]bool{}, &ReplayOptions{From: big.NewInt(0), Relay: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := store.Get(missed); r.Status != DepositSeen {
+		t.Fatalf("got status %s after replaying in a dry run, expected %s", r.Status, DepositSeen)
+	}
+
+	// a foreign contract cannot be withdrawn from, so the relay fails before a tx is sent
+	dest.ContractType = ForeignContract
+	if replayDeposit(origin, allChains, deposit(missed, dest.Id), false) {
+		t.Fatal("relayed a deposit to a foreign contract")
+	}
+	if r := store.Get(missed); r.Status != DepositFailed || r.Dest != dest.Name {
+		t.Fatalf("got status %s to %s, expected %s to %s", r.Status, r.Dest, DepositFailed, dest.Name)
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaults of RpcPolicy
+const (
+	defaultRetries       = 3
+	defaultRetryDelay    = 500 * time.Millisecond
+	defaultMaxRetryDelay = 10 * time.Second
+)
+
+// RpcPolicy limits the rate of requests to the endpoints of a chain and retries the requests that fail
+// with transient errors, eg. rate limiting, timeouts or dropped connections
+type RpcPolicy struct {
+	RateLimit     float64 `json:"rateLimit,omitempty"`     // requests per second to each endpoint; 0 is unlimited
+	Burst         int     `json:"burst,omitempty"`         // requests that can be made at once before the rate limit applies
+	Retries       *int    `json:"retries,omitempty"`       // times to retry a request after a transient error; defaults to 3
+	RetryDelay    int64   `json:"retryDelay,omitempty"`    // milliseconds before the first retry, doubling each time; defaults to 500
+	MaxRetryDelay int64   `json:"maxRetryDelay,omitempty"` // milliseconds; defaults to 10000
+}
+
+func (p *RpcPolicy) retries() int {
+	if p == nil || p.Retries == nil {
+		return defaultRetries
+	}
+	return *p.Retries
+}
+
+// how long to wait before retry number attempt, counting from 0, with jitter so the relayers
+// of several chains sharing a provider do not retry in lockstep
+func (p *RpcPolicy) backoff(attempt int) time.Duration {
+	delay, max := defaultRetryDelay, defaultMaxRetryDelay
+	if p != nil && p.RetryDelay > 0 {
+		delay = time.Duration(p.RetryDelay) * time.Millisecond
+	}
+	if p != nil && p.MaxRetryDelay > 0 {
+		max = time.Duration(p.MaxRetryDelay) * time.Millisecond
+	}
+
+	d := float64(delay) * math.Pow(2, float64(attempt))
+	if d > float64(max) {
+		d = float64(max)
+	}
+	return time.Duration(d/2 + rand.Float64()*d/2)
+}
+
+// the rate limit of an endpoint: its own if it sets one, otherwise the chain's
+func (p *RpcPolicy) limiter(e *Endpoint) *tokenBucket {
+	if e.RateLimit > 0 {
+		return newTokenBucket(e.RateLimit, e.Burst)
+	}
+	if p != nil && p.RateLimit > 0 {
+		return newTokenBucket(p.RateLimit, p.Burst)
+	}
+	return nil
+}
+
+// tokenBucket allows rate requests a second on average, and up to burst at once.
+// a nil bucket does not limit anything
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait takes a token, waiting for one if there are none left, unless ctx is done first
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// statusError is an http response from a node that is not 200 OK
+type statusError struct {
+	code       int
+	status     string
+	retryAfter time.Duration // from the Retry-After header of a 429 or 503, if any
+}
+
+func (e *statusError) Error() string {
+	return "rpc request failed: " + e.status
+}
+
+func newStatusError(resp *http.Response) *statusError {
+	err := &statusError{code: resp.StatusCode, status: resp.Status}
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+		err.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// errRateLimited is a json-rpc error some hosted providers return with status 200 when rate limiting
+var errRateLimited = fmt.Errorf("rpc request failed: rate limited")
+
+// whether a request that failed with err may succeed if it is tried again
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	// ethclient returns the errors of the http transport wrapped in the url of the request
+	if u, ok := err.(*url.Error); ok {
+		err = u.Err
+	}
+	if err == errRateLimited {
+		return true
+	}
+	if s, ok := err.(*statusError); ok {
+		switch s.code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if n, ok := err.(net.Error); ok && (n.Timeout() || n.Temporary()) {
+		return true
+	}
+	msg := err.Error()
+	for _, transient := range []string{"connection reset", "connection refused", "broken pipe", "EOF", "no such host", "timeout"} {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
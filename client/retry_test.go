@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(100, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		b.Wait(context.Background())
+	}
+	// 2 at once, then 2 more at 100 a second
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("4 requests took %s, expected the last 2 to wait about 20ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newTokenBucket(0.001, 1).Wait(ctx); err != nil {
+		t.Fatalf("got %v for the first token, expected none", err)
+	}
+	b = newTokenBucket(0.001, 1)
+	b.Wait(context.Background())
+	if err := b.Wait(ctx); err != context.Canceled {
+		t.Fatalf("got %v waiting with a cancelled context, expected %v", err, context.Canceled)
+	}
+
+	var unlimited *tokenBucket
+	if err := unlimited.Wait(ctx); err != nil {
+		t.Fatalf("got %v from a nil bucket, expected none", err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{&statusError{code: http.StatusTooManyRequests}, true},
+		{&statusError{code: http.StatusServiceUnavailable}, true},
+		{&statusError{code: http.StatusUnauthorized}, false},
+		{&statusError{code: http.StatusNotFound}, false},
+		{errRateLimited, true},
+		{errors.New("read tcp 127.0.0.1:8545: connection reset by peer"), true},
+		{errors.New("unexpected EOF"), true},
+		{errors.New("invalid argument 0: hex string has odd length"), false},
+	}
+	for _, test := range tests {
+		if got := isTransient(test.err); got != test.transient {
+			t.Errorf("isTransient(%v) = %v, expected %v", test.err, got, test.transient)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+		}
+	}))
+	defer server.Close()
+
+	retries := 2
+	chain := &Chain{Name: "test", Url: server.URL, RpcPolicy: &RpcPolicy{Retries: &retries, RetryDelay: 1}}
+	client := &http.Client{Transport: newFailoverTransport(chain, http.DefaultTransport)}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("got %v after %d requests, expected to succeed on the third", err, requests)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"result":"0x1"`) {
+		t.Fatalf("got %s, expected the result", body)
+	}
+
+	// no retries left
+	requests = 0
+	retries = 0
+	_, err = client.Post(server.URL, "application/json", strings.NewReader(`{"id":1}`))
+	if err == nil {
+		t.Fatal("got no error without retries")
+	}
+}
@@ -0,0 +1,346 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// decodes the data of a log into a typed event, eg. *DepositEvent
+type Decoder func(log types.Log) (interface{}, error)
+
+// handles a decoded event found on chain
+type Handler func(chain *Chain, log types.Log, event interface{}) error
+
+// how to decode and handle one event of one contract
+type Route struct {
+	Name   string
+	Decode Decoder
+	Handle Handler
+}
+
+// Router maps a contract address and event signature to the route for that event.
+// the addresses and signatures registered make up the filter query for the chain.
+type Router struct {
+	routes map[common.Address]map[common.Hash]*Route
+}
+
+func NewRouter() *Router {
+	return &Router{
+		routes: make(map[common.Address]map[common.Hash]*Route),
+	}
+}
+
+func (r *Router) Register(contract common.Address, sig common.Hash, route *Route) {
+	if r.routes[contract] == nil {
+		r.routes[contract] = make(map[common.Hash]*Route)
+	}
+	r.routes[contract][sig] = route
+}
+
+// register every route in routes for contract
+func (r *Router) RegisterAll(contract common.Address, routes map[common.Hash]*Route) {
+	for sig, route := range routes {
+		r.Register(contract, sig, route)
+	}
+}
+
+// the route for log, or nil if the contract or event is not registered
+func (r *Router) Route(log types.Log) *Route {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	return r.routes[log.Address][log.Topics[0]]
+}
+
+// filter query matching every registered contract and event
+func (r *Router) Query() ethereum.FilterQuery {
+	addresses := []common.Address{}
+	sigs := []common.Hash{}
+	seen := make(map[common.Hash]bool)
+	for contract, routes := range r.routes {
+		addresses = append(addresses, contract)
+		for sig := range routes {
+			if !seen[sig] {
+				sigs = append(sigs, sig)
+				seen[sig] = true
+			}
+		}
+	}
+
+	return ethereum.FilterQuery{
+		Addresses: addresses,
+		Topics:    [][]common.Hash{sigs},
+	}
+}
+
+// Dispatch decodes log and passes it to the handler of its route.
+// returns false if there is no route for the log.
+func (r *Router) Dispatch(chain *Chain, log types.Log) (bool, error) {
+	route := r.Route(log)
+	if route == nil {
+		return false, nil
+	}
+
+	event, err := route.Decode(log)
+	if err != nil {
+		return true, fmt.Errorf("could not decode %s event in tx %s: %s", route.Name, log.TxHash.Hex(), err)
+	}
+
+	if route.Handle == nil {
+		return true, nil
+	}
+	return true, route.Handle(chain, log, event)
+}
+
+/***** bridge contract routes ******/
+
+// routes for the events of the Bridge contract. every route just prints the event;
+// replace the handler of a route to act on it.
+func bridgeRoutes() map[common.Hash]*Route {
+	return map[common.Hash]*Route{
+		common.HexToHash(events.DepositId): {
+			Name:   "Deposit",
+			Decode: func(log types.Log) (interface{}, error) { return parseDeposit(log) },
+			Handle: printDeposit,
+		},
+		common.HexToHash(events.WithdrawId): {
+			Name:   "Withdraw",
+			Decode: func(log types.Log) (interface{}, error) { return parseWithdraw(log) },
+			Handle: printWithdraw,
+		},
+		common.HexToHash(events.SignedForWithdrawId): {
+			Name:   "SignedForWithdraw",
+			Decode: func(log types.Log) (interface{}, error) { return parseSigned(log) },
+			Handle: printSigned,
+		},
+		common.HexToHash(events.CreationId): {
+			Name:   "ContractCreation",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("bridge contract creation: owner"),
+		},
+		common.HexToHash(events.BridgeFundedId): {
+			Name:   "BridgeFunded",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("funded bridge event: from"),
+		},
+		common.HexToHash(events.PaidId): {
+			Name:   "Paid",
+			Decode: func(log types.Log) (interface{}, error) { return parsePaid(log) },
+			Handle: printPaid,
+		},
+		common.HexToHash(events.AuthorityAddedId): {
+			Name:   "AuthorityAdded",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("authority added:"),
+		},
+		common.HexToHash(events.AuthorityRemovedId): {
+			Name:   "AuthorityRemoved",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("authority removed:"),
+		},
+		common.HexToHash(events.ThresholdUpdatedId): {
+			Name:   "ThresholdUpdated",
+			Decode: func(log types.Log) (interface{}, error) { return parseThreshold(log) },
+			Handle: printThreshold,
+		},
+	}
+}
+
+/***** foreign and home contract routes ******/
+
+// routes for the events of the Foreign contract, which users deposit to on the chain being bridged out of
+func foreignRoutes() map[common.Hash]*Route {
+	return map[common.Hash]*Route{
+		common.HexToHash(events.DepositId): {
+			Name:   "Deposit",
+			Decode: func(log types.Log) (interface{}, error) { return parseDeposit(log) },
+			Handle: printDeposit,
+		},
+		common.HexToHash(events.CreationId): {
+			Name:   "ContractCreation",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("foreign contract creation: owner"),
+		},
+		common.HexToHash(events.ForeignWithdrawId): {
+			Name:   "Withdraw",
+			Decode: func(log types.Log) (interface{}, error) { return parseAmount(log) },
+			Handle: printOwnerWithdraw,
+		},
+	}
+}
+
+// routes for the events of the Home contract, which the relayer withdraws from on the chain being bridged into
+func homeRoutes() map[common.Hash]*Route {
+	return map[common.Hash]*Route{
+		common.HexToHash(events.WithdrawId): {
+			Name:   "Withdraw",
+			Decode: func(log types.Log) (interface{}, error) { return parseWithdraw(log) },
+			Handle: printWithdraw,
+		},
+		common.HexToHash(events.CreationId): {
+			Name:   "ContractCreation",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("home contract creation: owner"),
+		},
+		common.HexToHash(events.BridgeFundedId): {
+			Name:   "BridgeFunded",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("funded home contract event: from"),
+		},
+		common.HexToHash(events.BridgeSetId): {
+			Name:   "BridgeSet",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printBridgeSet,
+		},
+	}
+}
+
+/***** wrapped token routes ******/
+
+// routes for the events of the Wrapped contract of a lock-mint pair. Burn has the same arguments
+// as Deposit and is relayed the same way, to release the locked ether on the origin chain.
+func wrappedRoutes() map[common.Hash]*Route {
+	return map[common.Hash]*Route{
+		common.HexToHash(events.BurnId): {
+			Name:   "Burn",
+			Decode: func(log types.Log) (interface{}, error) { return parseDeposit(log) },
+			Handle: printBurn,
+		},
+		common.HexToHash(events.MintId): {
+			Name:   "Mint",
+			Decode: func(log types.Log) (interface{}, error) { return parseWithdraw(log) },
+			Handle: printMint,
+		},
+		common.HexToHash(events.CreationId): {
+			Name:   "ContractCreation",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printAddressEvent("wrapped contract creation: owner"),
+		},
+		common.HexToHash(events.BridgeSetId): {
+			Name:   "BridgeSet",
+			Decode: func(log types.Log) (interface{}, error) { return parseAddressEvent(log) },
+			Handle: printBridgeSet,
+		},
+	}
+}
+
+// routes for the contract deployed on chain
+func contractRoutes(chain *Chain) map[common.Hash]*Route {
+	switch chain.ContractType {
+	case ForeignContract:
+		return foreignRoutes()
+	case HomeContract:
+		return homeRoutes()
+	case WrappedContract:
+		return wrappedRoutes()
+	}
+	return bridgeRoutes()
+}
+
+// register the routes of the contract on chain; deposits are relayed to their destination in allChains.
+// a deposit waiting for room in the relay queue is given up on once ctx is cancelled
+func RegisterContractRoutes(ctx context.Context, router *Router, chain *Chain, allChains []*Chain) {
+	routes := contractRoutes(chain)
+	if deposit, ok := routes[common.HexToHash(depositEventId(chain))]; ok {
+		printEvent := deposit.Handle
+		deposit.Handle = func(chain *Chain, log types.Log, event interface{}) error {
+			depositsSeen.Inc(chain.Name)
+			printEvent(chain, log, event)
+			deposit := event.(*DepositEvent)
+			// deposits seen again, eg. when rescanning, are not relayed twice
+			record := store.Seen(chain, deposit)
+			if record.Status == DepositRelayed || record.Status == DepositExecuted {
+				logFor(chain, log).Info("deposit already %s, not relaying it again", record.Status)
+				return nil
+			}
+			return pool.Enqueue(ctx, chain, allChains, deposit)
+		}
+	}
+	if withdraw, ok := routes[common.HexToHash(withdrawEventId(chain))]; ok {
+		printEvent := withdraw.Handle
+		withdraw.Handle = func(chain *Chain, log types.Log, event interface{}) error {
+			hash := event.(*WithdrawEvent).DepositHash
+			// a withdraw that has been executed no longer counts against the balance of the contract
+			if paysOut(chain) {
+				LiquidityOf(chain).Release(hash)
+			}
+			store.SetStatus(hash, chain.Name, DepositExecuted, nil)
+			return printEvent(chain, log, event)
+		}
+	}
+	router.RegisterAll(*chain.Contract, routes)
+}
+
+// logger with the chain, tx hash and block of log as context
+func logFor(chain *Chain, log types.Log) *logger.Logger {
+	return logger.With(logger.Fields{"chain": chain.Name, "tx": log.TxHash.Hex(), "block": log.BlockNumber})
+}
+
+func printDeposit(chain *Chain, log types.Log, event interface{}) error {
+	deposit := event.(*DepositEvent)
+	logFor(chain, log).With(logger.Fields{"recipient": deposit.Recipient.Hex(), "value": deposit.Value.String(), "toChain": deposit.ToChain.String()}).Event("deposit event")
+	return nil
+}
+
+func printWithdraw(chain *Chain, log types.Log, event interface{}) error {
+	withdraw := event.(*WithdrawEvent)
+	logFor(chain, log).With(logger.Fields{"recipient": withdraw.Recipient.Hex(), "value": withdraw.Value.String(), "fromChain": withdraw.FromChain.String(), "deposit": withdraw.DepositHash.Hex()}).Event("withdraw event")
+	return nil
+}
+
+func printBurn(chain *Chain, log types.Log, event interface{}) error {
+	burn := event.(*DepositEvent)
+	logFor(chain, log).With(logger.Fields{"recipient": burn.Recipient.Hex(), "value": burn.Value.String(), "toChain": burn.ToChain.String()}).Event("burn event")
+	return nil
+}
+
+func printMint(chain *Chain, log types.Log, event interface{}) error {
+	mint := event.(*WithdrawEvent)
+	logFor(chain, log).With(logger.Fields{"recipient": mint.Recipient.Hex(), "value": mint.Value.String(), "fromChain": mint.FromChain.String(), "deposit": mint.DepositHash.Hex()}).Event("mint event")
+	return nil
+}
+
+func printSigned(chain *Chain, log types.Log, event interface{}) error {
+	signed := event.(*SignedEvent)
+	logFor(chain, log).With(logger.Fields{"authority": signed.Authority.Hex(), "deposit": signed.DepositHash.Hex()}).Event("signed for withdraw event")
+	return nil
+}
+
+func printPaid(chain *Chain, log types.Log, event interface{}) error {
+	paid := event.(*PaidEvent)
+	logFor(chain, log).With(logger.Fields{"addr": paid.Addr.Hex(), "value": paid.Value.String()}).Event("bridge paid event")
+	return nil
+}
+
+func printThreshold(chain *Chain, log types.Log, event interface{}) error {
+	logFor(chain, log).Event("threshold updated to %s: tx hash: %s", event.(*ThresholdEvent).Threshold, log.TxHash.Hex())
+	return nil
+}
+
+func printAddressEvent(msg string) Handler {
+	return func(chain *Chain, log types.Log, event interface{}) error {
+		logFor(chain, log).Event("%s %s: tx hash: %s", msg, event.(*AddressEvent).Addr.Hex(), log.TxHash.Hex())
+		return nil
+	}
+}
+
+func printOwnerWithdraw(chain *Chain, log types.Log, event interface{}) error {
+	logFor(chain, log).Event("owner withdrew %s wei from foreign contract: tx hash: %s", event.(*AmountEvent).Amount, log.TxHash.Hex())
+	return nil
+}
+
+func printBridgeSet(chain *Chain, log types.Log, event interface{}) error {
+	bridge := event.(*AddressEvent).Addr
+	l := logFor(chain, log)
+	l.Event("bridge set to %s: tx hash: %s", bridge.Hex(), log.TxHash.Hex())
+	if chain.From != nil && bridge != *chain.From {
+		l.Warn("bridge of %s contract on %s is no longer %s, relaying to it will fail", chain.ContractType, chain.Name, chain.From.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRouterDispatch(t *testing.T) {
+	bridge := common.HexToAddress("0x288a9fb92921472d29ab0b3c3e420a8e4bd4f452")
+	other := common.HexToAddress("0x51F4A0f0D3bf30600d07396dAde1eE2e4Bca9b5e")
+	sig := common.HexToHash("0x01")
+
+	handled := 0
+	router := NewRouter()
+	router.Register(bridge, sig, &Route{
+		Name:   "Test",
+		Decode: func(log types.Log) (interface{}, error) { return parseThreshold(log) },
+		Handle: func(chain *Chain, log types.Log, event interface{}) error {
+			if event.(*ThresholdEvent).Threshold.Int64() != 2 {
+				t.Errorf("decoded threshold -- got: %s expected: 2", event.(*ThresholdEvent).Threshold)
+			}
+			handled++
+			return nil
+		},
+	})
+
+	data := common.BigToHash(common.Big2).Bytes()
+	logs := []types.Log{
+		{Address: bridge, Topics: []common.Hash{sig}, Data: data},
+		{Address: other, Topics: []common.Hash{sig}, Data: data},
+		{Address: bridge, Topics: []common.Hash{common.HexToHash("0x02")}, Data: data},
+		{Address: bridge},
+	}
+
+	expected := []bool{true, false, false, false}
+	for i, log := range logs {
+		ok, err := router.Dispatch(nil, log)
+		if err != nil {
+			t.Fatalf("Dispatch returned error: %s", err)
+		}
+		if ok != expected[i] {
+			t.Errorf("log %d routed -- got: %t expected: %t", i, ok, expected[i])
+		}
+	}
+	if handled != 1 {
+		t.Errorf("handled %d logs, expected 1", handled)
+	}
+
+	_, err := router.Dispatch(nil, types.Log{Address: bridge, Topics: []common.Hash{sig}})
+	if err == nil {
+		t.Error("expected error decoding log with no data")
+	}
+}
+
+func TestRouterQuery(t *testing.T) {
+	bridge := common.HexToAddress("0x288a9fb92921472d29ab0b3c3e420a8e4bd4f452")
+	router := NewRouter()
+	router.Register(bridge, common.HexToHash("0x01"), &Route{})
+	router.Register(bridge, common.HexToHash("0x02"), &Route{})
+
+	query := router.Query()
+	if len(query.Addresses) != 1 || query.Addresses[0] != bridge {
+		t.Errorf("query addresses -- got: %v expected: [%s]", query.Addresses, bridge.Hex())
+	}
+	if len(query.Topics) != 1 || len(query.Topics[0]) != 2 {
+		t.Errorf("query topics -- got: %v expected 2 event signatures", query.Topics)
+	}
+}
+
+func TestRescanReadsLogsAgain(t *testing.T) {
+	name := "rescan-test"
+	all := func(uint64) bool { return true }
+	defer forgetLogs(name, all)
+	defer forgetLogs("other-chain", all)
+	log := func(block uint64, index uint) types.Log {
+		return types.Log{TxHash: common.HexToHash("0x01"), BlockNumber: block, Index: index}
+	}
+	if !markRead(name, log(10, 0)) || markRead(name, log(10, 0)) {
+		t.Fatal("expected a log to be read once")
+	}
+	markRead(name, log(20, 1))
+	if !markRead("other-chain", log(10, 0)) {
+		t.Fatal("logs read on another chain should not count")
+	}
+
+	// only the logs of the rescanned blocks are read again
+	Rescan(name, big.NewInt(15))
+	defer takeRescan(name)
+	if markRead(name, log(10, 0)) {
+		t.Error("log before the rescan was read again")
+	}
+	if !markRead(name, log(20, 1)) {
+		t.Error("log in the rescan was not read again")
+	}
+}
+
+func TestFilterShutDownWhileReading(t *testing.T) {
+	node := newBridgeNode(50)
+	chain, closeNode := testChain(t, "filter-shutdown", 1, node)
+	defer closeNode()
+	defer forgetLogs(chain.Name, func(uint64) bool { return true })
+	sig := common.HexToHash("0x5e")
+	node.update(func() {
+		node.logs = []types.Log{{Address: *chain.Contract, Topics: []common.Hash{sig}, Data: []byte{}, TxHash: common.HexToHash("0x01"), BlockNumber: 10}}
+	})
+
+	// shut down while a log is handled, eg. while waiting for room in the relay queue
+	ctx, cancel := context.WithCancel(context.Background())
+	router := NewRouter()
+	router.Register(*chain.Contract, sig, &Route{
+		Name:   "Test",
+		Decode: func(log types.Log) (interface{}, error) { return nil, nil },
+		Handle: func(chain *Chain, log types.Log, event interface{}) error {
+			cancel()
+			return ctx.Err()
+		},
+	})
+
+	// the chunk is not counted as scanned, so it is scanned again on the next start
+	last, err := Filter(ctx, chain, router, NewScanner(chain, router.Query()), big.NewInt(0), big.NewInt(50))
+	if err != context.Canceled || last != nil {
+		t.Fatalf("got last block %v and %v, expected no block and %v", last, err, context.Canceled)
+	}
+}
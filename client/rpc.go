@@ -1,231 +1,219 @@
 package client
 
 import (
-	"fmt"
-	"encoding/json"
-	"net/http"
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
-	"encoding/hex"
+	"net/http"
+	"sync/atomic"
+	"time"
 
-	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-/* rpc methods 
-/* used when making http requests
+/* rpc methods
+/* used where ethclient does not have a method for a call, or to make calls in batches
 */
 
-// used for json format a response from an RPC call
-type Resp struct {
-	jsonrpc string
-	id int
-	result string
+// timeout of a request made with an http client that does not set one
+const rpcTimeout = 30 * time.Second
+
+// RpcClient makes json-rpc calls to a node over http, one at a time or in batches
+type RpcClient struct {
+	url    string
+	client *http.Client
+	id     uint64
 }
 
-// used to json format an RPC call
-type Call struct {
-	Jsonrpc string 		`json:"jsonrpc"`
-	Method string 		`json:"method"`
-	Params []string 	`json:"params"`
-	Id int 				`json:"id"`
+// NewRpcClient makes calls to url with client; a client without a timeout gets one of 30s
+func NewRpcClient(url string, client *http.Client) *RpcClient {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if client.Timeout == 0 {
+		c := *client
+		c.Timeout = rpcTimeout
+		client = &c
+	}
+	return &RpcClient{url: url, client: client}
 }
 
-// this function makes the rpc call "eth_getLogs" passing in jsonParams as the json formatted
-// parameters to the call
-// json parameters: [optional] fromBlock, toBlock
-func getLogs(url string, jsonParams string, client *http.Client) (*http.Response, error) {
-	jsonStr := `{"jsonrpc":"2.0","method":"eth_getLogs","params":[` + jsonParams + `],"id":74}`
-	jsonBytes := []byte(jsonStr)
-	//fmt.Println(string(jsonBytes))
+// RpcError is an error object returned by the node
+type RpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
 
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
-	if err != nil { return nil, err }
-	return resp, nil
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
 }
 
-// this function makes the rpc call "eth_getTransactionReceipt" passing in the txHash
-func getTxReceipt(txHash string, url string, client *http.Client) (string, error) {
-    jsonStr := `{"jsonrpc":"2.0","method":"eth_getTransactionReceipt","params":["` + txHash + `"],"id":1}`
-    jsonBytes := []byte(jsonStr)
-    //fmt.Println(string(jsonBytes))
-    fmt.Println("getting tx receipt...")
+type rpcRequest struct {
+	Jsonrpc string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
 
-    req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-    req.Header.Set("Content-Type", "application/json")
-    resp, err := client.Do(req)
-    if err != nil { return "", err }
+type rpcResponse struct {
+	Id     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RpcError       `json:"error"`
+}
 
-	defer resp.Body.Close()
+// a call in a batch. Result is decoded into if the call succeeds, otherwise Error is set
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result interface{}
+	Error  error
+}
 
-	body, _ := ioutil.ReadAll(resp.Body)
-	receipt, err := ParseJsonForResult(string(body))
+// Call calls method with params and decodes its result into result, unless result is nil.
+// a result of null is returned as ethereum.NotFound
+func (c *RpcClient) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	body, err := c.send(ctx, c.request(method, params))
 	if err != nil {
-		fmt.Println("could not parse logs")
-		fmt.Println(err)
-		return "", err
+		return err
 	}
-	return receipt, nil
-}
-
-// this function calles the JSON RPC method "eth_getRawTransactionByHash" and returns the raw tx data if there is no error
-func getRawTx(txData string, url string, client *http.Client) (string, error) {
-    jsonStr := `{"jsonrpc":"2.0","method":"eth_getRawTransactionByHash","params":["` + txData + `"],"id":1}`
-    jsonBytes := []byte(jsonStr)
-    //fmt.Println("\n", string(jsonBytes))
-    fmt.Println("getting raw tx data...")
-
-    req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-    if err != nil { return "", err }
-    req.Header.Set("Content-Type", "application/json")
-    resp, err := client.Do(req)
-    if err != nil { return "", err }
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	//fmt.Println(string(body))
-	rawTx, err := ParseJsonForResult(string(body))
+	resp := rpcResponse{}
+	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		fmt.Println("could not parse logs")
-		fmt.Println(err)
-		return "", err
+		return fmt.Errorf("invalid rpc response: %s", err)
 	}
-	return rawTx, nil
-}
-
-func getTxCount(txData string, url string, client *http.Client) (*http.Response, error) {
-    jsonStr := `{"jsonrpc":"2.0","method":"eth_getTransactionCount","params":[` + txData + `],"id":1}`
-    jsonBytes := []byte(jsonStr)
-    //fmt.Println(string(jsonBytes))
-    fmt.Println("geting nonce...")
-
-    req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-    if err != nil { return nil, err }
-    req.Header.Set("Content-Type", "application/json")
-    resp, err := client.Do(req)
-    //fmt.Println(resp)
-    if err != nil { return nil, err }
-    return resp, nil
+	return resp.decode(result)
 }
 
-func sendTx(txData string, url string, client *http.Client) (*http.Response, error) {
-    jsonStr := `{"jsonrpc":"2.0","method":"eth_sendTransaction","params":[` + txData + `],"id":1}`
-    jsonBytes := []byte(jsonStr)
-    //fmt.Println("\n", string(jsonBytes))
-    fmt.Println("sending tx with params: ", txData)
-
-    req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-    if err != nil { return nil, err }
-    req.Header.Set("Content-Type", "application/json")
-    resp, err := client.Do(req)
-    //fmt.Println(resp)
-    if err != nil { return nil, err }
-    return resp, nil
-}
+// BatchCall sends every call in batch in a single request. the error returned is for the request as a
+// whole; the error of each call is set on its BatchElem
+func (c *RpcClient) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
 
-func sendRawTx(txData string, url string, client *http.Client) (*http.Response, error) {
-	fmt.Println("sending raw transaction with data: ", txData)
-    jsonStr := `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":["` + txData + `"],"id":1}`
-    jsonBytes := []byte(jsonStr)
-    //fmt.Println("\n", string(jsonBytes))
-
-    req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-    if err != nil { return nil, err }
-    req.Header.Set("Content-Type", "application/json")
-    resp, err := client.Do(req)
-    //fmt.Println(resp)
-    if err != nil { return nil, err }
-    return resp, nil
-}
+	requests := make([]rpcRequest, len(batch))
+	byId := make(map[uint64]int, len(batch))
+	for i, elem := range batch {
+		requests[i] = c.request(elem.Method, elem.Params)
+		byId[requests[i].Id] = i
+	}
 
-// this function gets the current block number by calling "eth_blockNumber"
-func getBlockNumber(url string) (string, error) {
-	client := &http.Client{}
-	var jsonBytes = []byte(`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":83}`)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-	req.Header.Set("Content-Type", "application/json")
-	blockNumResp, err := client.Do(req)
+	body, err := c.send(ctx, requests)
 	if err != nil {
-       	return "", err
+		return err
 	}
-	defer blockNumResp.Body.Close()
-	blockNumBody, _ := ioutil.ReadAll(blockNumResp.Body)
-
-	// parse json for result
-	startBlock, err := ParseJsonForResult(string(blockNumBody))
+	responses := []rpcResponse{}
+	err = json.Unmarshal(body, &responses)
 	if err != nil {
-		return "", nil
+		// some nodes answer a batch they cannot handle at all with a single error
+		single := rpcResponse{}
+		if json.Unmarshal(body, &single) == nil && single.Error != nil {
+			return single.Error
+		}
+		return fmt.Errorf("invalid rpc response: %s", err)
+	}
+
+	// responses can come back in any order
+	answered := make([]bool, len(batch))
+	for _, r := range responses {
+		i, ok := byId[r.Id]
+		if !ok {
+			continue
+		}
+		answered[i] = true
+		batch[i].Error = r.decode(batch[i].Result)
 	}
-	return startBlock, nil
+	for i := range batch {
+		if !answered[i] {
+			batch[i].Error = errors.New("no response to rpc call")
+		}
+	}
+	return nil
 }
 
-// this function gets the current block by calling "eth_getBlockByNumber"
-func getBlockByNumber(url string, number string) (string, error) {
-	client := &http.Client{}
-	var jsonBytes = []byte(`{"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["` + number + `",false],"id":1}`)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBytes))
-	req.Header.Set("Content-Type", "application/json")
-	blockNumResp, err := client.Do(req)
-	if err != nil {
-       	return "", err
+func (c *RpcClient) request(method string, params []interface{}) rpcRequest {
+	if params == nil {
+		params = []interface{}{}
 	}
-	defer blockNumResp.Body.Close()
-	blockNumBody, _ := ioutil.ReadAll(blockNumResp.Body)
+	return rpcRequest{Jsonrpc: "2.0", Id: atomic.AddUint64(&c.id, 1), Method: method, Params: params}
+}
 
-	// parse json for result
-	res, err := ParseJsonForResult(string(blockNumBody))
+// post a request or batch of requests to the node and return the body of the response
+func (c *RpcClient) send(ctx context.Context, request interface{}) ([]byte, error) {
+	body, err := json.Marshal(request)
 	if err != nil {
-		return "", nil
+		return nil, err
 	}
-	return res, nil
-}
+	req, err := http.NewRequest("POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
 
-func getNonce(address []byte, url string) (string) {
-	// get nonce
-	client := &http.Client{}
-	txCountData := fmt.Sprintf("\"0x%x\", \"latest\"", address)
-	resp, err := getTxCount(txCountData, url, client)
+	resp, err := c.client.Do(req)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
 	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	nonce, err := ParseJsonForResult(string(body))
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println("could not parse logs")
-		fmt.Println(err)
+		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rpc request failed: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+	return respBody, nil
+}
 
-	return nonce
+// decode the result of r into result, unless it is nil
+func (r *rpcResponse) decode(result interface{}) error {
+	switch {
+	case r.Error != nil:
+		return r.Error
+	case len(r.Result) == 0 || string(r.Result) == "null":
+		return ethereum.NotFound
+	case result != nil:
+		return json.Unmarshal(r.Result, result)
+	}
+	return nil
 }
 
-/*****  helpers *****/
-type JsonRpcResponse struct {
-	Id int					`json:"id,omitempty"`
-	Jsonrpc string 			`json:"jsonrpc,omitempty"`
-	Result string 			`json:"result"`
+// BlockNumber returns the number of the latest block
+func (c *RpcClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var number hexutil.Uint64
+	err := c.Call(ctx, &number, "eth_blockNumber")
+	return uint64(number), err
 }
 
-// this function parses jsonStr for the entry "result" and returns its value as a string
-func ParseJsonForResult(jsonStr string) (string, error) {
-	jsonBody := []byte(jsonStr)
-	resp := new(JsonRpcResponse)
-	err := json.Unmarshal(jsonBody, resp)
+// LookupTx asks for the receipt of txHash and for the tx itself in a single request, returning the
+// receipt if it has been mined and whether the node knows about the tx at all
+func (c *RpcClient) LookupTx(ctx context.Context, txHash common.Hash) (*types.Receipt, bool, error) {
+	receipt := new(types.Receipt)
+	var tx json.RawMessage
+	batch := []BatchElem{
+		{Method: "eth_getTransactionReceipt", Params: []interface{}{txHash}, Result: receipt},
+		{Method: "eth_getTransactionByHash", Params: []interface{}{txHash}, Result: &tx},
+	}
+	err := c.BatchCall(ctx, batch)
 	if err != nil {
-		return "", err
+		return nil, false, err
+	}
+	if batch[0].Error == nil {
+		return receipt, true, nil
+	}
+	if batch[0].Error != ethereum.NotFound {
+		return nil, false, batch[0].Error
 	}
-	return resp.Result, nil
+	if batch[1].Error == ethereum.NotFound {
+		return nil, false, nil
+	}
+	return nil, batch[1].Error == nil, batch[1].Error
 }
-
-func rlpDecodeTx(rawTxData string) (*types.Transaction) {
-	tx := new(types.Transaction)
-    rawtx,err := hex.DecodeString(rawTxData)
-    if err != nil {
-    	fmt.Println(err)
-    }
-    rlp.DecodeBytes(rawtx, &tx)
-	return tx
-}
\ No newline at end of file
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// a node that answers eth_blockNumber, fails eth_call and has no result for anything else
+func rpcHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	answer := func(req rpcRequest) map[string]interface{} {
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id}
+		switch req.Method {
+		case "eth_blockNumber":
+			resp["result"] = "0x10"
+		case "eth_call":
+			resp["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		default:
+			resp["result"] = nil
+		}
+		return resp
+	}
+
+	batch := []rpcRequest{}
+	if json.Unmarshal(body, &batch) == nil {
+		// answer in reverse order, which nodes are allowed to do
+		resps := []map[string]interface{}{}
+		for i := len(batch) - 1; i >= 0; i-- {
+			resps = append(resps, answer(batch[i]))
+		}
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	req := rpcRequest{}
+	json.Unmarshal(body, &req)
+	json.NewEncoder(w).Encode(answer(req))
+}
+
+func TestRpcClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(rpcHandler))
+	defer server.Close()
+	c := NewRpcClient(server.URL, nil)
+	ctx := context.Background()
+
+	number, err := c.BlockNumber(ctx)
+	if err != nil || number != 16 {
+		t.Fatalf("got block %d, %v, expected 16", number, err)
+	}
+
+	err = c.Call(ctx, nil, "eth_call")
+	if rpcErr, ok := err.(*RpcError); !ok || rpcErr.Code != -32000 || rpcErr.Message != "execution reverted" {
+		t.Fatalf("got error %v, expected rpc error -32000", err)
+	}
+
+	err = c.Call(ctx, new(types.Receipt), "eth_getTransactionReceipt", common.Hash{})
+	if err != ethereum.NotFound {
+		t.Fatalf("got error %v for null result, expected %v", err, ethereum.NotFound)
+	}
+
+	var head hexutil.Uint64
+	batch := []BatchElem{
+		{Method: "eth_blockNumber", Result: &head},
+		{Method: "eth_call"},
+		{Method: "eth_getTransactionByHash", Params: []interface{}{"0x00"}},
+	}
+	err = c.BatchCall(ctx, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil || head != 16 {
+		t.Fatalf("got block %d, %v in batch, expected 16", head, batch[0].Error)
+	}
+	if _, ok := batch[1].Error.(*RpcError); !ok {
+		t.Fatalf("got %v in batch, expected rpc error", batch[1].Error)
+	}
+	if batch[2].Error != ethereum.NotFound {
+		t.Fatalf("got %v in batch, expected %v", batch[2].Error, ethereum.NotFound)
+	}
+}
+
+func TestRpcClientHttpError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewRpcClient(server.URL, nil).BlockNumber(context.Background())
+	if err == nil {
+		t.Fatal("got no error for http 429")
+	}
+}
+
+func TestLookupTx(t *testing.T) {
+	node := newBridgeNode(50)
+	server := httptest.NewServer(node)
+	defer server.Close()
+	c := NewRpcClient(server.URL, nil)
+	ctx := context.Background()
+	pendingHash, minedHash := common.HexToHash("0xa1"), common.HexToHash("0xa2")
+	node.update(func() {
+		node.pending[pendingHash] = types.NewTransaction(0, common.Address{}, common.Big0, 21000, common.Big1, nil)
+		node.receipts[minedHash] = &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: minedHash, Logs: []*types.Log{}}
+	})
+
+	tests := []struct {
+		name  string
+		hash  common.Hash
+		mined bool
+		known bool
+	}{
+		{"pending", pendingHash, false, true},
+		{"mined", minedHash, true, true},
+		{"unknown", common.HexToHash("0xa3"), false, false},
+	}
+	for _, test := range tests {
+		receipt, known, err := c.LookupTx(ctx, test.hash)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if (receipt != nil) != test.mined || known != test.known {
+			t.Errorf("%s: got receipt %v and known %t, expected mined %t and known %t", test.name, receipt, known, test.mined, test.known)
+		}
+	}
+}
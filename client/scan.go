@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+const (
+	DefaultMaxBlockRange = 5000 // blocks per eth_getLogs query if the chain config does not set maxBlockRange
+	sparseLogs           = 100  // grow the chunk size if a query returns fewer logs than this
+)
+
+// substrings of the errors providers return when a query covers too many blocks or results
+// eg. infura: "query returned more than 10000 results"
+var rangeErrors = []string{
+	"query returned more than",
+	"block range",
+	"range too large",
+	"response size exceeded",
+	"response too large",
+	"too many blocks",
+	"is limited to a",
+}
+
+// Scanner queries logs over a block range in chunks. the chunk size is halved when the provider
+// rejects a query for being too large and doubled, up to max, when results are sparse.
+type Scanner struct {
+	chain *Chain
+	query ethereum.FilterQuery
+	size  uint64 // current chunk size
+	max   uint64
+}
+
+type ChunkHandler func(logs []types.Log, from, to uint64) error
+
+func NewScanner(chain *Chain, query ethereum.FilterQuery) *Scanner {
+	max := chain.MaxBlockRange
+	if max == 0 {
+		max = DefaultMaxBlockRange
+	}
+	return &Scanner{
+		chain: chain,
+		query: query,
+		size:  max,
+		max:   max,
+	}
+}
+
+// returns true if err is a provider rejecting a query for covering too many blocks or results.
+// a node rate limiting the relayer, eg. "429 Too Many Requests", is not, since a smaller query would not help
+func isRangeError(err error) bool {
+	// ethclient returns the errors of the http transport wrapped in the url of the request
+	cause := err
+	if u, ok := err.(*url.Error); ok {
+		cause = u.Err
+	}
+	if _, ok := cause.(*statusError); ok || cause == errRateLimited {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range rangeErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// work out the next chunk size given the outcome of the last query
+func nextChunkSize(size, max uint64, logs int, rangeErr bool) uint64 {
+	if rangeErr {
+		if size > 1 {
+			return size / 2
+		}
+		return 1
+	}
+	if logs < sparseLogs && size < max {
+		size *= 2
+		if size > max {
+			return max
+		}
+	}
+	return size
+}
+
+// Scan queries logs from block `from` to block `to`, inclusive. handle is called with the logs of
+// every chunk in order; it is called for empty chunks too, so it can be used to record progress.
+// Scan stops at the first error returned by handle, or once ctx is cancelled.
+func (s *Scanner) Scan(ctx context.Context, from, to uint64, handle ChunkHandler) error {
+	for start := from; start <= to; {
+		end := start + s.size - 1
+		if end > to {
+			end = to
+		}
+
+		query := s.query
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+
+		logs, err := s.chain.Client.FilterLogs(ctx, query)
+		if err != nil {
+			if !isRangeError(err) || s.size == 1 {
+				return err
+			}
+			s.size = nextChunkSize(s.size, s.max, 0, true)
+			if flags["v"] {
+				logger.Info("query of blocks %d to %d on %s too large, retrying with %d blocks", start, end, s.chain.Name, s.size)
+			}
+			continue
+		}
+
+		err = handle(logs, start, end)
+		if err != nil {
+			return err
+		}
+
+		s.size = nextChunkSize(s.size, s.max, len(logs), false)
+		start = end + 1
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestIsRangeError(t *testing.T) {
+	rangeErrs := []string{
+		"query returned more than 10000 results",
+		"exceed maximum block range: 5000",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+	}
+	for _, msg := range rangeErrs {
+		if !isRangeError(errors.New(msg)) {
+			t.Errorf("expected %q to be a range error", msg)
+		}
+	}
+
+	notRangeErrs := []error{
+		errors.New("connection refused"),
+		errors.New("rpc request failed: 429 Too Many Requests: daily request count exceeded, request rate limited"),
+		&statusError{code: http.StatusTooManyRequests, status: "429 Too Many Requests"},
+		&url.Error{Op: "Post", URL: "http://127.0.0.1:8545", Err: &statusError{code: http.StatusTooManyRequests, status: "429 Too Many Requests"}},
+		&url.Error{Op: "Post", URL: "http://127.0.0.1:8545", Err: errRateLimited},
+	}
+	for _, err := range notRangeErrs {
+		if isRangeError(err) {
+			t.Errorf("expected %q not to be a range error", err)
+		}
+	}
+}
+
+func TestNextChunkSize(t *testing.T) {
+	cases := []struct {
+		size, max uint64
+		logs      int
+		rangeErr  bool
+		expected  uint64
+	}{
+		{1000, 1000, 0, true, 500},
+		{1, 1000, 0, true, 1},
+		{500, 1000, 0, false, 1000},
+		{800, 1000, 10, false, 1000},
+		{500, 1000, sparseLogs, false, 500},
+		{1000, 1000, 0, false, 1000},
+	}
+
+	for _, c := range cases {
+		actual := nextChunkSize(c.size, c.max, c.logs, c.rangeErr)
+		if actual != c.expected {
+			t.Errorf("nextChunkSize(%d, %d, %d, %t) -- got: %d expected: %d", c.size, c.max, c.logs, c.rangeErr, actual, c.expected)
+		}
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	chain, closeNode := testChain(t, "scan-cancelled", 1, newBridgeNode(50))
+	defer closeNode()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a query started after shutdown fails rather than being retried
+	err := NewScanner(chain, ethereum.FilterQuery{}).Scan(ctx, 0, 50, func(logs []types.Log, from, to uint64) error {
+		t.Fatalf("got blocks %d to %d after the scan was cancelled", from, to)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("got no error for a cancelled scan")
+	}
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Signer signs txs and messages for the relayer account of a chain
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error)
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// kinds of signer
+const (
+	KeystoreSignerType = "keystore" // a key in the local keystore, decrypted in process
+	ClefSignerType     = "clef"     // clef, or another signer with its json-rpc api
+	RemoteSignerType   = "remote"   // an http signing service; see RemoteSigner
+)
+
+// how long an external signer has to sign, eg. for someone to approve the tx in clef
+const signTimeout = 2 * time.Minute
+
+// which signer a chain uses, from config.json
+type SignerConfig struct {
+	Type  string `json:"type"`
+	Url   string `json:"url,omitempty"`   // of clef or the remote signer
+	Token string `json:"token,omitempty"` // sent to the remote signer as "Authorization: Bearer <token>"
+}
+
+// whether config is for the local keystore, in which case the account has to be unlocked
+func (config *SignerConfig) IsKeystore() bool {
+	return config == nil || config.Type == "" || config.Type == KeystoreSignerType
+}
+
+// NewSigner returns the signer for address described by config; the account unlocked in the local
+// keystore ks if config is nil
+func NewSigner(config *SignerConfig, ks *keystore.KeyStore, address common.Address) (Signer, error) {
+	if config.IsKeystore() {
+		return NewUnlockedSigner(ks, address), nil
+	}
+	if config.Url == "" {
+		return nil, fmt.Errorf("%s signer needs a url", config.Type)
+	}
+	switch config.Type {
+	case ClefSignerType:
+		return NewClefSigner(config.Url, address), nil
+	case RemoteSignerType:
+		return NewRemoteSigner(config.Url, config.Token, address), nil
+	}
+	return nil, fmt.Errorf("unknown signer type %q: expected keystore, clef or remote", config.Type)
+}
+
+// the signer of chain, set up with NewSigner when the chain is loaded
+func signerOf(chain *Chain) (Signer, error) {
+	if chain.Signer == nil {
+		return nil, fmt.Errorf("chain %s has no signer", chain.Name)
+	}
+	return chain.Signer, nil
+}
+
+// KeystoreSigner signs with a key in the local keystore, unlocked by UnlockAccount
+type KeystoreSigner struct {
+	keys    *keystore.KeyStore
+	account accounts.Account
+}
+
+// NewUnlockedSigner signs with address, which must have been unlocked in ks
+func NewUnlockedSigner(ks *keystore.KeyStore, address common.Address) *KeystoreSigner {
+	return &KeystoreSigner{keys: ks, account: accounts.Account{Address: address}}
+}
+
+func (s *KeystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return s.keys.SignTx(s.account, tx, chainId)
+}
+
+func (s *KeystoreSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.keys.SignHash(s.account, hash)
+}
+
+// the fields of a tx as external signers take them
+type txArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainId  *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+func newTxArgs(from common.Address, tx *types.Transaction, chainId *big.Int) txArgs {
+	return txArgs{
+		From:     from,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Value:    (*hexutil.Big)(tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     hexutil.Bytes(tx.Data()),
+		ChainId:  (*hexutil.Big)(chainId),
+	}
+}
+
+// decode a tx signed by an external signer, and check it is tx signed by from;
+// the signer must not be able to change what is sent
+func verifySignedTx(raw []byte, tx *types.Transaction, chainId *big.Int, from common.Address) (*types.Transaction, error) {
+	signed := new(types.Transaction)
+	err := rlp.DecodeBytes(raw, signed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signed tx: %s", err)
+	}
+
+	signer := types.NewEIP155Signer(chainId)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, errors.New("signed tx does not match the tx sent to the signer")
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %s", err)
+	}
+	if sender != from {
+		return nil, fmt.Errorf("tx was signed by %s, not %s", sender.Hex(), from.Hex())
+	}
+	return signed, nil
+}
+
+// ClefSigner signs with clef over its json-rpc api, so the key never leaves clef
+type ClefSigner struct {
+	rpc     *RpcClient
+	address common.Address
+}
+
+func NewClefSigner(url string, address common.Address) *ClefSigner {
+	return &ClefSigner{rpc: NewRpcClient(url, &http.Client{Timeout: signTimeout}), address: address}
+}
+
+func (s *ClefSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *ClefSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	result := struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}{}
+	err := s.rpc.Call(context.Background(), &result, "account_signTransaction", newTxArgs(s.address, tx, chainId))
+	if err != nil {
+		return nil, fmt.Errorf("clef could not sign tx: %s", err)
+	}
+	return verifySignedTx(result.Raw, tx, chainId, s.address)
+}
+
+// clef only signs data it can show to whoever approves it, never a bare hash
+func (s *ClefSigner) SignHash(hash []byte) ([]byte, error) {
+	return nil, errors.New("clef does not sign hashes")
+}
+
+// RemoteSigner signs with an http signing service, which must answer
+//
+//	POST <url>/sign/tx   {"from", "to", "gas", "gasPrice", "value", "nonce", "data", "chainId"}, as hex
+//	                     with {"raw": <signed tx, rlp encoded, as hex>}
+//	POST <url>/sign/hash {"from", "hash"} with {"signature": <65 byte signature as hex>}
+//
+// every signature it returns is checked before it is used
+type RemoteSigner struct {
+	url     string
+	token   string
+	client  *http.Client
+	address common.Address
+}
+
+func NewRemoteSigner(url string, token string, address common.Address) *RemoteSigner {
+	return &RemoteSigner{url: strings.TrimSuffix(url, "/"), token: token, client: &http.Client{Timeout: signTimeout}, address: address}
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	result := struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}{}
+	err := s.post("/sign/tx", newTxArgs(s.address, tx, chainId), &result)
+	if err != nil {
+		return nil, err
+	}
+	return verifySignedTx(result.Raw, tx, chainId, s.address)
+}
+
+func (s *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	request := struct {
+		From common.Address `json:"from"`
+		Hash hexutil.Bytes  `json:"hash"`
+	}{s.address, hash}
+	result := struct {
+		Signature hexutil.Bytes `json:"signature"`
+	}{}
+	err := s.post("/sign/hash", request, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := crypto.SigToPub(hash, result.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %s", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("hash was signed by %s, not %s", signer.Hex(), s.address.Hex())
+	}
+	return result.Signature, nil
+}
+
+func (s *RemoteSigner) post(path string, request interface{}, result interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer: %s", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("remote signer: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+	return json.Unmarshal(respBody, result)
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// a remote signing service holding key, which signs the tx it is sent after passing it to tamper
+func signingService(t *testing.T, key *ecdsa.PrivateKey, tamper func(args *txArgs)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/sign/tx":
+			args := txArgs{}
+			json.NewDecoder(r.Body).Decode(&args)
+			tamper(&args)
+			tx := types.NewTransaction(uint64(args.Nonce), *args.To, args.Value.ToInt(), uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+			signed, err := types.SignTx(tx, types.NewEIP155Signer(args.ChainId.ToInt()), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, _ := rlp.EncodeToBytes(signed)
+			json.NewEncoder(w).Encode(map[string]interface{}{"raw": hexutil.Bytes(raw)})
+		case "/sign/hash":
+			req := struct {
+				Hash hexutil.Bytes `json:"hash"`
+			}{}
+			json.NewDecoder(r.Body).Decode(&req)
+			sig, _ := crypto.Sign(req.Hash, key)
+			json.NewEncoder(w).Encode(map[string]interface{}{"signature": hexutil.Bytes(sig)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainId := big.NewInt(42)
+	tx := types.NewTransaction(7, common.HexToAddress("0x1"), big.NewInt(100), 4600000, big.NewInt(1e9), []byte{1, 2, 3})
+
+	server := signingService(t, key, func(args *txArgs) {})
+	defer server.Close()
+	signer := NewRemoteSigner(server.URL+"/", "secret", address)
+
+	signed, err := signer.SignTx(tx, chainId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, _ := types.Sender(types.NewEIP155Signer(chainId), signed)
+	if sender != address || signed.Nonce() != 7 {
+		t.Fatalf("signed tx from %s with nonce %d", sender.Hex(), signed.Nonce())
+	}
+
+	hash := crypto.Keccak256([]byte("message"))
+	sig, err := signer.SignHash(hash)
+	if err != nil || len(sig) != 65 {
+		t.Fatalf("signature %x: %v", sig, err)
+	}
+
+	// the signature must be by the account the signer is for
+	other, _ := crypto.GenerateKey()
+	_, err = NewRemoteSigner(server.URL, "secret", crypto.PubkeyToAddress(other.PublicKey)).SignTx(tx, chainId)
+	if err == nil {
+		t.Fatal("accepted a tx signed by another account")
+	}
+
+	_, err = NewRemoteSigner(server.URL, "wrong", address).SignTx(tx, chainId)
+	if err == nil {
+		t.Fatal("signed without the token")
+	}
+
+	// a signer that changes the tx
+	tampering := signingService(t, key, func(args *txArgs) { args.Value = (*hexutil.Big)(big.NewInt(1e18)) })
+	defer tampering.Close()
+	_, err = NewRemoteSigner(tampering.URL, "secret", address).SignTx(tx, chainId)
+	if err == nil {
+		t.Fatal("accepted a tx that was changed by the signer")
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	for _, config := range []*SignerConfig{nil, {Type: "keystore"}} {
+		signer, err := NewSigner(config, nil, address)
+		if _, ok := signer.(*KeystoreSigner); !ok || err != nil {
+			t.Fatalf("%v: got %T, %v", config, signer, err)
+		}
+	}
+
+	signer, err := NewSigner(&SignerConfig{Type: "clef", Url: "http://127.0.0.1:8550"}, nil, address)
+	if _, ok := signer.(*ClefSigner); !ok || err != nil {
+		t.Fatalf("clef: got %T, %v", signer, err)
+	}
+	if signer.Address() != address {
+		t.Fatalf("clef signer is for %s", signer.Address().Hex())
+	}
+
+	for _, config := range []*SignerConfig{{Type: "remote"}, {Type: "hsm", Url: "http://127.0.0.1"}} {
+		_, err := NewSigner(config, nil, address)
+		if err == nil {
+			t.Fatalf("%+v: expected an error", config)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// kinds of start block
+const (
+	StartNumber     = "number"     // a block number, eg. "7000000"
+	StartLatest     = "latest"     // the latest block minus an offset, eg. "latest" or "latest-100"
+	StartDeployment = "deployment" // the block the bridge contract was deployed in
+)
+
+type StartBlockSpec struct {
+	Kind   string
+	Number *big.Int // block number for StartNumber, offset for StartLatest
+}
+
+var errFound = errors.New("found")
+
+// ParseStartBlock parses a start block as given in config.json or on the command line:
+// a block number, "latest", "latest-N" or "deployment"
+func ParseStartBlock(spec string) (*StartBlockSpec, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	switch {
+	case spec == StartDeployment:
+		return &StartBlockSpec{Kind: StartDeployment}, nil
+	case spec == StartLatest:
+		return &StartBlockSpec{Kind: StartLatest, Number: big.NewInt(0)}, nil
+	case strings.HasPrefix(spec, StartLatest+"-"):
+		offset, ok := new(big.Int).SetString(spec[len(StartLatest)+1:], 10)
+		if !ok || offset.Sign() < 0 {
+			return nil, fmt.Errorf("invalid offset in start block %q", spec)
+		}
+		return &StartBlockSpec{Kind: StartLatest, Number: offset}, nil
+	}
+
+	number, ok := new(big.Int).SetString(spec, 10)
+	if !ok || number.Sign() < 0 {
+		return nil, fmt.Errorf("invalid start block %q: expected a block number, \"latest\", \"latest-N\" or \"deployment\"", spec)
+	}
+	return &StartBlockSpec{Kind: StartNumber, Number: number}, nil
+}
+
+// ResolveStartBlock works out the block number a start block refers to on chain
+func ResolveStartBlock(chain *Chain, e *Events, spec *StartBlockSpec) (*big.Int, error) {
+	events = e
+
+	switch spec.Kind {
+	case StartNumber:
+		return new(big.Int).Set(spec.Number), nil
+	case StartLatest:
+		head, err := chainHead(context.Background(), chain, headMaxAge)
+		if err != nil {
+			return nil, err
+		}
+		start := new(big.Int).Sub(head, spec.Number)
+		if start.Sign() < 0 {
+			return new(big.Int), nil
+		}
+		return start, nil
+	case StartDeployment:
+		return FindDeploymentBlock(chain)
+	}
+	return nil, fmt.Errorf("unknown start block kind %s", spec.Kind)
+}
+
+// FindDeploymentBlock finds the block the bridge contract on chain was deployed in.
+// it first binary searches for the first block with code at the contract address, which needs
+// a node that keeps historical state. if the node cannot serve that, it scans for the
+// ContractCreation event from the genesis block instead.
+func FindDeploymentBlock(chain *Chain) (*big.Int, error) {
+	ctx := context.Background()
+	head, err := chainHead(ctx, chain, headMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := chain.Client.CodeAt(ctx, *chain.Contract, head)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no contract deployed at %s on %s", chain.Contract.Hex(), chain.Name)
+	}
+
+	lo, hi := big.NewInt(0), new(big.Int).Set(head)
+	for lo.Cmp(hi) < 0 {
+		mid := new(big.Int).Add(lo, hi)
+		mid.Rsh(mid, 1)
+
+		code, err := chain.Client.CodeAt(ctx, *chain.Contract, mid)
+		if err != nil {
+			logger.Warn("could not get historical code on %s: %s: scanning for contract creation instead", chain.Name, err)
+			return findCreationEvent(chain, head)
+		}
+
+		if len(code) == 0 {
+			lo = mid.Add(mid, big.NewInt(1))
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// scan from the genesis block to head for the ContractCreation event of the bridge contract
+func findCreationEvent(chain *Chain, head *big.Int) (*big.Int, error) {
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{*chain.Contract},
+		Topics:    [][]common.Hash{{common.HexToHash(events.CreationId)}},
+	}
+
+	var block *big.Int
+	err := NewScanner(chain, query).Scan(context.Background(), 0, head.Uint64(), func(logs []types.Log, start, end uint64) error {
+		if len(logs) == 0 {
+			return nil
+		}
+		block = new(big.Int).SetUint64(logs[0].BlockNumber)
+		return errFound
+	})
+	if err != nil && err != errFound {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("could not find contract creation of %s on %s", chain.Contract.Hex(), chain.Name)
+	}
+	return block, nil
+}
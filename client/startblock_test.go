@@ -0,0 +1,39 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestParseStartBlock(t *testing.T) {
+	cases := []struct {
+		spec   string
+		kind   string
+		number int64
+	}{
+		{"7000000", StartNumber, 7000000},
+		{"0", StartNumber, 0},
+		{"latest", StartLatest, 0},
+		{"latest-100", StartLatest, 100},
+		{" Deployment ", StartDeployment, -1},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseStartBlock(c.spec)
+		if err != nil {
+			t.Fatalf("ParseStartBlock(%q) returned error: %s", c.spec, err)
+		}
+		if actual.Kind != c.kind {
+			t.Errorf("ParseStartBlock(%q) kind -- got: %s expected: %s", c.spec, actual.Kind, c.kind)
+		}
+		if c.number >= 0 && actual.Number.Int64() != c.number {
+			t.Errorf("ParseStartBlock(%q) number -- got: %s expected: %d", c.spec, actual.Number, c.number)
+		}
+	}
+
+	for _, spec := range []string{"", "-1", "latest-", "latest-x", "earliest"} {
+		_, err := ParseStartBlock(spec)
+		if err == nil {
+			t.Errorf("expected ParseStartBlock(%q) to fail", spec)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// end-to-end state of a transfer
+const (
+	StatePending       = "pending"               // deposit tx not yet mined
+	StateUnknownChain  = "unknown chain"         // deposit is to a chain that is not in the config
+	StateAwaitingRelay = "awaiting relay"        // deposit mined, no authority has signed yet
+	StateSigning       = "collecting signatures" // some authorities signed, threshold not reached
+	StateExecuted      = "executed"              // withdraw executed on the destination chain
+)
+
+// storage slots of the Bridge contract, see solidity/Bridge/Bridge.sol
+// threshold is not public, so it is read directly from storage
+var (
+	thresholdSlot  = common.BigToHash(big.NewInt(2))
+	withdrawalSlot = common.BigToHash(big.NewInt(5))
+)
+
+var ErrDepositNotFound = errors.New("no deposit event found in tx")
+
+type TransferStatus struct {
+	State          string
+	Origin         *Chain
+	Destination    *Chain
+	Deposit        *DepositEvent
+	Confirmations  uint64
+	Signatures     []*SignedEvent
+	SignatureCount *big.Int
+	Threshold      *big.Int
+	Withdraw       *WithdrawEvent
+}
+
+// find the Deposit event emitted by the bridge contract in the tx with hash txHash,
+// or the Burn event if the contract is a wrapped token
+func findDeposit(chain *Chain, txHash common.Hash) (*DepositEvent, *types.Receipt, error) {
+	receipt, err := chain.Client.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Address != *chain.Contract || len(log.Topics) == 0 {
+			continue
+		}
+		if log.Topics[0].Hex() == depositEventId(chain) {
+			deposit, err := parseDeposit(*log)
+			if err != nil {
+				return nil, nil, err
+			}
+			return deposit, receipt, nil
+		}
+	}
+	return nil, receipt, ErrDepositNotFound
+}
+
+// find the Withdraw and SignedForWithdraw events on chain for the deposit with hash depositHash.
+// on a wrapped token, the Mint event takes the place of Withdraw.
+func findWithdraw(chain *Chain, depositHash common.Hash) (*WithdrawEvent, []*SignedEvent, error) {
+	withdrawId := withdrawEventId(chain)
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{*chain.Contract},
+		Topics: [][]common.Hash{{
+			common.HexToHash(withdrawId),
+			common.HexToHash(events.SignedForWithdrawId),
+		}},
+	}
+
+	head, err := chainHead(context.Background(), chain, headMaxAge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var withdraw *WithdrawEvent
+	signatures := []*SignedEvent{}
+	err = NewScanner(chain, query).Scan(context.Background(), 0, head.Uint64(), func(logs []types.Log, start, end uint64) error {
+		for _, log := range logs {
+			switch log.Topics[0].Hex() {
+			case withdrawId:
+				w, err := parseWithdraw(log)
+				if err == nil && w.DepositHash == depositHash {
+					withdraw = w
+				}
+			case events.SignedForWithdrawId:
+				s, err := parseSigned(log)
+				if err == nil && s.DepositHash == depositHash {
+					signatures = append(signatures, s)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return withdraw, signatures, nil
+}
+
+// read the number of signatures collected for depositHash and the signature threshold
+// from the bridge contract's storage
+func signatureCount(chain *Chain, depositHash common.Hash) (*big.Int, *big.Int, error) {
+	ctx := context.Background()
+
+	threshold, err := chain.Client.StorageAt(ctx, *chain.Contract, thresholdSlot, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// location of withdrawal[depositHash] is keccak256(key . slot)
+	key := crypto.Keccak256Hash(depositHash.Bytes(), withdrawalSlot.Bytes())
+	count, err := chain.Client.StorageAt(ctx, *chain.Contract, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return new(big.Int).SetBytes(count), new(big.Int).SetBytes(threshold), nil
+}
+
+// GetTransferStatus looks up the deposit made in txHash on chain and follows it to its destination chain
+func GetTransferStatus(chain *Chain, allChains []*Chain, e *Events, txHash common.Hash) (*TransferStatus, error) {
+	events = e
+	status := &TransferStatus{Origin: chain}
+
+	deposit, receipt, err := findDeposit(chain, txHash)
+	if err == ethereum.NotFound {
+		// no receipt yet, check if the tx is known at all
+		_, isPending, err := chain.Client.TransactionByHash(context.Background(), txHash)
+		if err != nil {
+			return nil, err
+		}
+		if isPending {
+			status.State = StatePending
+			return status, nil
+		}
+		return nil, fmt.Errorf("no receipt found for tx %s", txHash.Hex())
+	} else if err != nil {
+		return nil, err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, fmt.Errorf("deposit tx %s failed", txHash.Hex())
+	}
+	status.Deposit = deposit
+
+	head, err := chainHead(context.Background(), chain, headMaxAge)
+	if err != nil {
+		return nil, err
+	}
+	if head.Cmp(receipt.BlockNumber) >= 0 {
+		status.Confirmations = new(big.Int).Sub(head, receipt.BlockNumber).Uint64() + 1
+	}
+
+	status.Destination = FindChain(deposit.ToChain, allChains)
+	if status.Destination == nil {
+		status.State = StateUnknownChain
+		return status, nil
+	}
+
+	status.Withdraw, status.Signatures, err = findWithdraw(status.Destination, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// the home and wrapped contracts withdraw or mint straight away, without collecting signatures
+	if status.Destination.ContractType == HomeContract || status.Destination.ContractType == WrappedContract {
+		if status.Withdraw != nil {
+			status.State = StateExecuted
+		} else {
+			status.State = StateAwaitingRelay
+		}
+		return status, nil
+	}
+
+	status.SignatureCount, status.Threshold, err = signatureCount(status.Destination, txHash)
+	if err != nil {
+		logger.Warn("could not read signature count from storage on %s: %s", status.Destination.Name, err)
+		status.SignatureCount = big.NewInt(int64(len(status.Signatures)))
+	}
+
+	if status.Withdraw != nil {
+		status.State = StateExecuted
+	} else if status.SignatureCount.Sign() > 0 {
+		status.State = StateSigning
+	} else {
+		status.State = StateAwaitingRelay
+	}
+
+	return status, nil
+}
+
+// print out a transfer status
+func PrintStatus(status *TransferStatus) {
+	logger.Info("transfer status: %s", status.State)
+	if status.Deposit == nil {
+		return
+	}
+
+	logger.Info("deposit on %s at block %d with %d confirmations", status.Origin.Name, status.Deposit.BlockNumber, status.Confirmations)
+	logger.Info("recipient: %s", status.Deposit.Recipient.Hex())
+	logger.Info("value: %s wei", status.Deposit.Value)
+	logger.Info("to chain: %s", status.Deposit.ToChain)
+	if status.Destination == nil {
+		return
+	}
+
+	// there is no signature count for a home contract
+	if status.SignatureCount != nil && status.Threshold != nil {
+		logger.Info("signatures on %s: %s of %s", status.Destination.Name, status.SignatureCount, status.Threshold)
+	} else if status.SignatureCount != nil {
+		logger.Info("signatures on %s: %s", status.Destination.Name, status.SignatureCount)
+	}
+	for _, sig := range status.Signatures {
+		logger.Info("signed by %s in tx %s", sig.Authority.Hex(), sig.TxHash.Hex())
+	}
+
+	if status.Withdraw != nil {
+		logger.Info("withdraw tx %s at block %d", status.Withdraw.TxHash.Hex(), status.Withdraw.BlockNumber)
+		logger.Info("amount received by %s: %s wei", status.Withdraw.Recipient.Hex(), status.Withdraw.Value)
+	}
+}
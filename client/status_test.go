@@ -0,0 +1,243 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var testEvents = &Events{
+	DepositId:           crypto.Keccak256Hash([]byte("Deposit(address,uint256,uint256)")).Hex(),
+	WithdrawId:          crypto.Keccak256Hash([]byte("Withdraw(address,uint256,uint256,bytes32)")).Hex(),
+	SignedForWithdrawId: crypto.Keccak256Hash([]byte("SignedForWithdraw(bytes32,address)")).Hex(),
+}
+
+// a node with a bridge contract, answering with the receipts, pending txs, logs, storage and nonce it is given.
+// eth_getLogs returns every log, whatever the query. batches are answered in order
+type bridgeNode struct {
+	mu       sync.Mutex
+	head     uint64
+	receipts map[common.Hash]*types.Receipt
+	pending  map[common.Hash]*types.Transaction
+	logs     []types.Log
+	storage  map[common.Hash]common.Hash
+	nonce    uint64               // pending nonce of every account
+	sent     []*types.Transaction // raw txs sent to the node
+}
+
+func newBridgeNode(head uint64) *bridgeNode {
+	return &bridgeNode{
+		head:     head,
+		receipts: map[common.Hash]*types.Receipt{},
+		pending:  map[common.Hash]*types.Transaction{},
+		storage:  map[common.Hash]common.Hash{},
+	}
+}
+
+// change what the node answers with
+func (n *bridgeNode) update(f func()) {
+	n.mu.Lock()
+	f()
+	n.mu.Unlock()
+}
+
+func (n *bridgeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	batch := []rpcCall{}
+	if json.Unmarshal(body, &batch) == nil {
+		resps := []interface{}{}
+		for _, req := range batch {
+			resps = append(resps, n.answer(req))
+		}
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	req := rpcCall{}
+	json.Unmarshal(body, &req)
+	json.NewEncoder(w).Encode(n.answer(req))
+}
+
+type rpcCall struct {
+	Id     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (n *bridgeNode) answer(req rpcCall) interface{} {
+	hash := common.Hash{}
+	if len(req.Params) > 0 {
+		json.Unmarshal(req.Params[0], &hash)
+	}
+
+	var result interface{}
+	switch req.Method {
+	case "eth_blockNumber":
+		result = hexutil.Uint64(n.head)
+	case "eth_getTransactionReceipt":
+		if receipt, ok := n.receipts[hash]; ok {
+			result = receipt
+		}
+	case "eth_getTransactionByHash":
+		if tx, ok := n.pending[hash]; ok {
+			result = tx
+		}
+	case "eth_getLogs":
+		result = n.logs
+	case "eth_getTransactionCount":
+		result = hexutil.Uint64(n.nonce)
+	case "eth_sendRawTransaction":
+		raw := hexutil.Bytes{}
+		json.Unmarshal(req.Params[0], &raw)
+		tx := new(types.Transaction)
+		rlp.DecodeBytes(raw, tx)
+		n.sent = append(n.sent, tx)
+		result = tx.Hash()
+	case "eth_getStorageAt":
+		slot := common.Hash{}
+		json.Unmarshal(req.Params[1], &slot)
+		result = hexutil.Bytes(n.storage[slot].Bytes())
+	}
+	return map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result}
+}
+
+// a chain connected to node
+func testChain(t *testing.T, name string, id int64, node *bridgeNode) (*Chain, func()) {
+	server := httptest.NewServer(node)
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.BigToAddress(big.NewInt(id))
+	from := common.HexToAddress("0xe8b7b81f281a947840de4b23f40442b3843c5f49")
+	chain := &Chain{Name: name, Id: big.NewInt(id), Contract: &contract, From: &from, Client: client, Rpc: NewRpcClient(server.URL, nil)}
+	return chain, server.Close
+}
+
+func depositLog(chain *Chain, txHash common.Hash, block uint64, recipient common.Address, value int64, toChain *big.Int) types.Log {
+	data := append(common.LeftPadBytes(recipient.Bytes(), 32), common.BigToHash(big.NewInt(value)).Bytes()...)
+	data = append(data, common.BigToHash(toChain).Bytes()...)
+	return types.Log{Address: *chain.Contract, Topics: []common.Hash{common.HexToHash(events.DepositId)}, Data: data, TxHash: txHash, BlockNumber: block}
+}
+
+func withdrawLog(chain *Chain, txHash common.Hash, block uint64, recipient common.Address, value int64, fromChain *big.Int, depositHash common.Hash) types.Log {
+	data := append(common.LeftPadBytes(recipient.Bytes(), 32), common.BigToHash(big.NewInt(value)).Bytes()...)
+	data = append(data, common.BigToHash(fromChain).Bytes()...)
+	data = append(data, depositHash.Bytes()...)
+	return types.Log{Address: *chain.Contract, Topics: []common.Hash{common.HexToHash(events.WithdrawId)}, Data: data, TxHash: txHash, BlockNumber: block}
+}
+
+func signedLog(chain *Chain, txHash common.Hash, block uint64, depositHash common.Hash, authority common.Address) types.Log {
+	data := append(depositHash.Bytes(), common.LeftPadBytes(authority.Bytes(), 32)...)
+	return types.Log{Address: *chain.Contract, Topics: []common.Hash{common.HexToHash(events.SignedForWithdrawId)}, Data: data, TxHash: txHash, BlockNumber: block}
+}
+
+func TestGetTransferStatus(t *testing.T) {
+	events = testEvents
+	originNode, destNode := newBridgeNode(100), newBridgeNode(50)
+	origin, closeOrigin := testChain(t, "status-origin", 1, originNode)
+	defer closeOrigin()
+	dest, closeDest := testChain(t, "status-dest", 2, destNode)
+	defer closeDest()
+	allChains := []*Chain{origin, dest}
+
+	recipient := common.HexToAddress("0x83a8e0bd54ff6dc11da80151563b8150534280be")
+	depositHash := common.HexToHash("0xd1")
+
+	// sent, not mined yet
+	key, _ := crypto.GenerateKey()
+	tx, _ := types.SignTx(types.NewTransaction(0, *origin.Contract, big.NewInt(10), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	originNode.update(func() { originNode.pending[depositHash] = tx })
+	status, err := GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil || status.State != StatePending {
+		t.Fatalf("got %+v, %v for a pending deposit, expected %s", status, err, StatePending)
+	}
+
+	// mined at block 91 of 100, with no signatures on dest yet
+	deposit := depositLog(origin, depositHash, 91, recipient, 10, dest.Id)
+	originNode.update(func() {
+		originNode.receipts[depositHash] = &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: depositHash, BlockNumber: big.NewInt(91), Logs: []*types.Log{&deposit}}
+	})
+	destNode.update(func() { destNode.storage[thresholdSlot] = common.BigToHash(big.NewInt(2)) })
+	status, err = GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != StateAwaitingRelay || status.Confirmations != 10 || status.Destination != dest {
+		t.Fatalf("got %s with %d confirmations, expected %s with 10", status.State, status.Confirmations, StateAwaitingRelay)
+	}
+	if status.Deposit.Recipient != recipient || status.Deposit.Value.Int64() != 10 {
+		t.Fatalf("got deposit %+v", status.Deposit)
+	}
+
+	// one of two signatures; signatures for other deposits are not counted
+	authority := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	countSlot := crypto.Keccak256Hash(depositHash.Bytes(), withdrawalSlot.Bytes())
+	destNode.update(func() {
+		destNode.logs = []types.Log{
+			signedLog(dest, common.HexToHash("0xe1"), 40, depositHash, authority),
+			signedLog(dest, common.HexToHash("0xe2"), 41, common.HexToHash("0xd2"), authority),
+		}
+		destNode.storage[countSlot] = common.BigToHash(big.NewInt(1))
+	})
+	status, err = GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != StateSigning || len(status.Signatures) != 1 || status.Signatures[0].Authority != authority {
+		t.Fatalf("got %s with signatures %v, expected %s with 1", status.State, status.Signatures, StateSigning)
+	}
+	if status.SignatureCount.Int64() != 1 || status.Threshold.Int64() != 2 {
+		t.Fatalf("got %s of %s signatures, expected 1 of 2", status.SignatureCount, status.Threshold)
+	}
+
+	// withdrawn once the threshold is reached
+	withdrawHash := common.HexToHash("0xe3")
+	destNode.update(func() {
+		destNode.logs = append(destNode.logs,
+			signedLog(dest, withdrawHash, 45, depositHash, common.HexToAddress("0x2222222222222222222222222222222222222222")),
+			withdrawLog(dest, withdrawHash, 45, recipient, 10, origin.Id, depositHash),
+		)
+		destNode.storage[countSlot] = common.BigToHash(big.NewInt(2))
+	})
+	status, err = GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != StateExecuted || status.Withdraw == nil || status.Withdraw.TxHash != withdrawHash || len(status.Signatures) != 2 {
+		t.Fatalf("got %s with withdraw %+v and %d signatures, expected %s in %s", status.State, status.Withdraw, len(status.Signatures), StateExecuted, withdrawHash.Hex())
+	}
+
+	// a home contract withdraws without signatures, so its storage is not read
+	dest.ContractType = HomeContract
+	destNode.update(func() {
+		destNode.logs = []types.Log{withdrawLog(dest, withdrawHash, 45, recipient, 10, origin.Id, depositHash)}
+	})
+	status, err = GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != StateExecuted || status.SignatureCount != nil {
+		t.Fatalf("got %s with %s signatures on a home contract, expected %s and none", status.State, status.SignatureCount, StateExecuted)
+	}
+
+	// a deposit to a chain that is not in the config
+	unknown := depositLog(origin, depositHash, 91, recipient, 10, big.NewInt(99))
+	originNode.update(func() { originNode.receipts[depositHash].Logs = []*types.Log{&unknown} })
+	status, err = GetTransferStatus(origin, allChains, testEvents, depositHash)
+	if err != nil || status.State != StateUnknownChain {
+		t.Fatalf("got %+v, %v, expected %s", status, err, StateUnknownChain)
+	}
+}
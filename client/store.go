@@ -0,0 +1,228 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// relay status of a deposit
+const (
+	DepositSeen     = "seen"     // found on the origin chain, not relayed yet
+	DepositRelayed  = "relayed"  // withdraw or mint sent to the destination chain
+	DepositDeferred = "deferred" // withdraw held back until the destination contract is funded
+	DepositFailed   = "failed"   // could not be relayed; see Error
+	DepositExecuted = "executed" // withdraw or mint executed on the destination chain
+)
+
+// number of deposits the store keeps; the oldest are dropped first
+const defaultStoreSize = 1000
+
+// a deposit and how far it has got to its destination chain
+type DepositRecord struct {
+	TxHash    common.Hash    `json:"txHash"`
+	Origin    string         `json:"origin"`
+	Dest      string         `json:"dest,omitempty"`
+	Recipient common.Address `json:"recipient"`
+	Value     *big.Int       `json:"value"`
+	ToChain   *big.Int       `json:"toChain"`
+	Block     uint64         `json:"block"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	Seen      time.Time      `json:"seen"`
+	Updated   time.Time      `json:"updated"`
+}
+
+// Store keeps the most recent deposits seen by the relayer and their status, saved to a file
+// so deposits that have already been relayed are not relayed again after a restart
+type Store struct {
+	mu       sync.Mutex
+	path     string // not saved if empty
+	size     int
+	deposits map[common.Hash]*DepositRecord
+}
+
+// deposits are only kept in memory until OpenStore is called
+var store = &Store{size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)}
+
+// OpenStore loads the deposits saved at path and saves every change to them there
+func OpenStore(path string) error {
+	s := &Store{path: path, size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		records := []*DepositRecord{}
+		err = json.Unmarshal(data, &records)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			s.deposits[r.TxHash] = r
+		}
+	}
+
+	store = s
+	return nil
+}
+
+// Seen records a deposit found on chain, unless it is already in the store
+func (s *Store) Seen(chain *Chain, deposit *DepositEvent) *DepositRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.deposits[deposit.TxHash]; ok {
+		last := *r
+		return &last
+	}
+
+	now := time.Now()
+	r := &DepositRecord{
+		TxHash:    deposit.TxHash,
+		Origin:    chain.Name,
+		Recipient: deposit.Recipient,
+		Value:     deposit.Value,
+		ToChain:   deposit.ToChain,
+		Block:     deposit.BlockNumber,
+		Status:    DepositSeen,
+		Seen:      now,
+		Updated:   now,
+	}
+	s.deposits[r.TxHash] = r
+	s.prune()
+	s.save()
+
+	last := *r
+	return &last
+}
+
+// SetStatus updates the status of the deposit with hash txHash; deposits not in the store are ignored
+func (s *Store) SetStatus(txHash common.Hash, dest string, status string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.deposits[txHash]
+	if !ok {
+		return
+	}
+	// a withdraw that has been executed stays executed, eg. if it is relayed again by another authority
+	if r.Status == DepositExecuted && status != DepositExecuted {
+		return
+	}
+
+	if dest != "" {
+		r.Dest = dest
+	}
+	r.Status = status
+	r.Error = ""
+	if err != nil {
+		r.Error = err.Error()
+	}
+	r.Updated = time.Now()
+	s.save()
+}
+
+// Get returns the deposit with hash txHash, or nil if it is not in the store
+func (s *Store) Get(txHash common.Hash) *DepositRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.deposits[txHash]
+	if !ok {
+		return nil
+	}
+	last := *r
+	return &last
+}
+
+// Recent returns up to limit deposits, newest first, from chain if it is not empty
+func (s *Store) Recent(chain string, limit int) []DepositRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := []DepositRecord{}
+	for _, r := range s.deposits {
+		if chain == "" || r.Origin == chain || r.Dest == chain {
+			records = append(records, *r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Seen.After(records[j].Seen) })
+
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
+
+// Unrelayed returns the deposits found on chain that have not been relayed yet, oldest first
+func (s *Store) Unrelayed(chain string) []DepositRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := []DepositRecord{}
+	for _, r := range s.deposits {
+		if r.Origin == chain && r.Status == DepositSeen {
+			records = append(records, *r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Block < records[j].Block })
+	return records
+}
+
+// the deposit event r was recorded from
+func (r *DepositRecord) event() *DepositEvent {
+	return &DepositEvent{
+		Recipient:   r.Recipient,
+		Value:       r.Value,
+		ToChain:     r.ToChain,
+		TxHash:      r.TxHash,
+		BlockNumber: r.Block,
+	}
+}
+
+// drop the oldest deposits beyond the size of the store; s.mu must be held
+func (s *Store) prune() {
+	if len(s.deposits) <= s.size {
+		return
+	}
+	records := make([]*DepositRecord, 0, len(s.deposits))
+	for _, r := range s.deposits {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Seen.Before(records[j].Seen) })
+	for _, r := range records[:len(records)-s.size] {
+		delete(s.deposits, r.TxHash)
+	}
+}
+
+// write the store to s.path; s.mu must be held
+func (s *Store) save() {
+	if s.path == "" {
+		return
+	}
+	records := make([]*DepositRecord, 0, len(s.deposits))
+	for _, r := range s.deposits {
+		records = append(records, r)
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		logger.Error("could not save deposits: %s", err)
+		return
+	}
+	err = ioutil.WriteFile(s.path+".tmp", data, 0644)
+	if err == nil {
+		err = os.Rename(s.path+".tmp", s.path)
+	}
+	if err != nil {
+		logger.Error("could not save deposits: %s", err)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { store = &Store{size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)} }()
+
+	path := filepath.Join(dir, "deposits.json")
+	err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &Chain{Name: "kovan", Id: big.NewInt(42)}
+	a := &DepositEvent{TxHash: common.HexToHash("0xa"), Value: big.NewInt(1), ToChain: big.NewInt(1)}
+	b := &DepositEvent{TxHash: common.HexToHash("0xb"), Value: big.NewInt(2), ToChain: big.NewInt(1)}
+
+	if r := store.Seen(chain, a); r.Status != DepositSeen {
+		t.Fatalf("got status %s for new deposit, expected %s", r.Status, DepositSeen)
+	}
+	store.Seen(chain, b)
+	store.SetStatus(a.TxHash, "ropsten", DepositRelayed, nil)
+	store.SetStatus(b.TxHash, "ropsten", DepositFailed, errors.New("out of gas"))
+
+	// seeing a deposit again keeps its status
+	if r := store.Seen(chain, a); r.Status != DepositRelayed || r.Dest != "ropsten" {
+		t.Fatalf("got %s to %q for relayed deposit, expected relayed to ropsten", r.Status, r.Dest)
+	}
+
+	// an executed withdraw stays executed
+	store.SetStatus(a.TxHash, "", DepositExecuted, nil)
+	store.SetStatus(a.TxHash, "", DepositRelayed, nil)
+
+	// deposits are loaded again after a restart
+	err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := store.Get(a.TxHash); r == nil || r.Status != DepositExecuted {
+		t.Fatalf("got %v after reopening, expected executed deposit", r)
+	}
+	if r := store.Get(b.TxHash); r == nil || r.Error != "out of gas" {
+		t.Fatalf("got %v after reopening, expected failed deposit with error", r)
+	}
+	if recent := store.Recent("kovan", 1); len(recent) != 1 {
+		t.Fatalf("got %d recent deposits, expected 1", len(recent))
+	}
+	if recent := store.Recent("rinkeby", 0); len(recent) != 0 {
+		t.Fatalf("got %d recent deposits on rinkeby, expected none", len(recent))
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	s := &Store{size: 2, deposits: make(map[common.Hash]*DepositRecord)}
+	chain := &Chain{Name: "kovan"}
+	for i := int64(1); i <= 3; i++ {
+		s.Seen(chain, &DepositEvent{TxHash: common.BigToHash(big.NewInt(i))})
+	}
+	if len(s.deposits) != 2 {
+		t.Fatalf("store has %d deposits, expected 2", len(s.deposits))
+	}
+	if s.Get(common.BigToHash(big.NewInt(3))) == nil {
+		t.Fatal("newest deposit was pruned")
+	}
+}
@@ -2,28 +2,42 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
-	"log"
-	"sync"
+
+	"github.com/ChainSafe/ChainBridge/logger"
 )
 
-func Cleanup(chain *Chain, lastBlock *big.Int, wg *sync.WaitGroup) {
-	f, err := os.Create("log/" + chain.Id.String() + "_lastblock.txt") // creating...
+// write the last block that has been fully scanned on chain to log/<id>_lastblock.txt
+// the file is written to a temporary file first so a crash cannot leave a truncated checkpoint
+func SaveCheckpoint(chain *Chain, lastBlock *big.Int) error {
+	path := "log/" + chain.Id.String() + "_lastblock.txt"
+	err := ioutil.WriteFile(path+".tmp", []byte(fmt.Sprintf("%d\n", lastBlock)), 0644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(path+".tmp", path)
 	if err != nil {
-	    fmt.Printf("error creating file: %v", err)
-	    return
+		return err
 	}
-	defer f.Close()
+	checkpointBlock.Set(bigFloat(lastBlock), chain.Name)
+	setCheckpoint(chain, lastBlock)
+	return nil
+}
 
+// save the last block scanned on chain when its listener stops; nothing is saved if no blocks were scanned,
+// so the checkpoint from the last run is kept
+func Cleanup(chain *Chain, lastBlock *big.Int) error {
+	l := logger.With(logger.Fields{"chain": chain.Name})
 	if lastBlock == nil {
-		log.Fatal("could not find last block for chain", chain.Id)
+		l.Info("no blocks scanned on chain %s, checkpoint unchanged", chain.Id)
+		return nil
 	}
-	fmt.Println("last block at chain", chain.Id, "is", lastBlock)
-	_, err = f.WriteString(fmt.Sprintf("%d\n", lastBlock)) // writing...
+	l.Info("last block at chain %s is %s", chain.Id, lastBlock)
+	err := SaveCheckpoint(chain, lastBlock)
 	if err != nil {
-	    fmt.Printf("error writing string: %v", err)
+		return fmt.Errorf("error writing checkpoint: %s", err)
 	}
-
-	wg.Done()
-}
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+	bridgecontract "github.com/ChainSafe/ChainBridge/solidity/Bridge"
+	homecontract "github.com/ChainSafe/ChainBridge/solidity/Home"
+	wrappedcontract "github.com/ChainSafe/ChainBridge/solidity/Wrapped"
+)
+
+// the contract deployed on a chain
+const (
+	BridgeContract  = "bridge"  // symmetric Bridge contract, deployed on both sides
+	ForeignContract = "foreign" // Foreign contract of a Foreign => Home pair, takes deposits
+	HomeContract    = "home"    // Home contract of a Foreign => Home pair, pays out withdraws
+	WrappedContract = "wrapped" // Wrapped token of a lock-mint pair, minted for ether locked on the origin chain
+)
+
+// topologies of a pair of chains in config.json
+const (
+	TopologyBridge      = "bridge"
+	TopologyForeignHome = "foreign-home"
+	TopologyLockMint    = "lock-mint"
+)
+
+// check that the relayer account is an authority of the Bridge contract on chain,
+// since Bridge.withdraw is only allowed from an authority
+func CheckAuthority(chain *Chain) error {
+	bridge, err := bridgecontract.NewBridgeCaller(*chain.Contract, chain.Client)
+	if err != nil {
+		return err
+	}
+
+	// isAuthority is not marked view, so the binding only sends it as a tx; call it instead
+	isAuthority := new(bool)
+	raw := &bridgecontract.BridgeCallerRaw{Contract: bridge}
+	err = raw.Call(&bind.CallOpts{}, isAuthority, "isAuthority", *chain.From)
+	if err != nil {
+		return fmt.Errorf("could not check authorities of bridge contract %s on %s: %s", chain.Contract.Hex(), chain.Name, err)
+	}
+	if !*isAuthority {
+		return fmt.Errorf("%s is not an authority of bridge contract %s on %s", chain.From.Hex(), chain.Contract.Hex(), chain.Name)
+	}
+	return nil
+}
+
+// check that the relayer account is the bridge of the Home contract on chain,
+// since Home.withdraw and funding Home are only allowed from the bridge
+func CheckHomeBridge(chain *Chain) error {
+	home, err := homecontract.NewHomeCaller(*chain.Contract, chain.Client)
+	if err != nil {
+		return err
+	}
+
+	bridge, err := home.Bridge(&bind.CallOpts{})
+	if err != nil {
+		return err
+	}
+	if bridge != *chain.From {
+		return fmt.Errorf("bridge of home contract %s on %s is %s, not %s", chain.Contract.Hex(), chain.Name, bridge.Hex(), chain.From.Hex())
+	}
+	return nil
+}
+
+// check that the relayer account is the bridge of the Wrapped contract on chain,
+// since Wrapped.mint is only allowed from the bridge
+func CheckWrappedBridge(chain *Chain) error {
+	wrapped, err := wrappedcontract.NewWrappedCaller(*chain.Contract, chain.Client)
+	if err != nil {
+		return err
+	}
+
+	bridge, err := wrapped.Bridge(&bind.CallOpts{})
+	if err != nil {
+		return err
+	}
+	if bridge != *chain.From {
+		return fmt.Errorf("bridge of wrapped contract %s on %s is %s, not %s", chain.Contract.Hex(), chain.Name, bridge.Hex(), chain.From.Hex())
+	}
+	return nil
+}
+
+// CheckSupply reconciles the supply of the wrapped token on chain against the ether locked
+// in the bridge contract on its origin chain. the origin bridge may hold more than the supply,
+// since it is also funded directly and takes deposits to other chains, but every wrapped token
+// must be backed, so a supply greater than the locked ether means tokens were minted without a deposit.
+// returns the supply and the locked ether.
+func CheckSupply(chain *Chain) (*big.Int, *big.Int, error) {
+	if chain.Origin == nil || chain.Origin.Client == nil {
+		return nil, nil, fmt.Errorf("origin chain of the wrapped token on %s is not loaded", chain.Name)
+	}
+
+	wrapped, err := wrappedcontract.NewWrappedCaller(*chain.Contract, chain.Client)
+	if err != nil {
+		return nil, nil, err
+	}
+	supply, err := wrapped.TotalSupply(&bind.CallOpts{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locked, err := chain.Origin.Client.BalanceAt(context.Background(), *chain.Origin.Contract, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if supply.Cmp(locked) > 0 {
+		return supply, locked, fmt.Errorf("supply of wrapped token on %s is %s wei, but only %s wei is locked on %s",
+			chain.Name, supply, locked, chain.Origin.Name)
+	}
+	return supply, locked, nil
+}
+
+// run CheckSupply and log the result
+func reconcileSupply(chain *Chain) {
+	supply, locked, err := CheckSupply(chain)
+	if err != nil {
+		logger.Error("supply reconciliation failed: %s", err)
+		return
+	}
+	if flags["v"] {
+		logger.Info("wrapped supply on %s: %s wei, locked on %s: %s wei", chain.Name, supply, chain.Origin.Name, locked)
+	}
+}
+
+// signature of the event a deposit to the contract on chain emits: Burn for a wrapped token, Deposit otherwise
+func depositEventId(chain *Chain) string {
+	if chain.ContractType == WrappedContract {
+		return events.BurnId
+	}
+	return events.DepositId
+}
+
+// signature of the event that completes a transfer to the contract on chain: Mint for a wrapped token, Withdraw otherwise
+func withdrawEventId(chain *Chain) string {
+	if chain.ContractType == WrappedContract {
+		return events.MintId
+	}
+	return events.WithdrawId
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// a node whose bridge contract has authorities as its only authorities
+func authorityNode(authorities ...common.Address) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Id     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		call := struct {
+			Data string `json:"data"`
+		}{}
+		json.Unmarshal(req.Params[0], &call)
+
+		result := "0x" + strings.Repeat("0", 64)
+		for _, authority := range authorities {
+			// the last 20 bytes of the call data are the address asked about
+			if strings.HasSuffix(strings.ToLower(call.Data), strings.ToLower(authority.Hex()[2:])) {
+				result = "0x" + strings.Repeat("0", 63) + "1"
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result})
+	}))
+}
+
+func TestCheckAuthority(t *testing.T) {
+	authority := common.HexToAddress("0xe8b7b81f281a947840de4b23f40442b3843c5f49")
+	other := common.HexToAddress("0x83a8e0bd54ff6dc11da80151563b8150534280be")
+	node := authorityNode(authority)
+	defer node.Close()
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contract := common.HexToAddress("0x288a9fb92921472d29ab0b3c3e420a8e4bd4f452")
+	chain := &Chain{Name: "kovan", Contract: &contract, From: &authority, Client: client}
+	if err := CheckAuthority(chain); err != nil {
+		t.Fatal(err)
+	}
+
+	chain.From = &other
+	if err := CheckAuthority(chain); err == nil || !strings.Contains(err.Error(), "not an authority") {
+		t.Fatalf("expected %s not to be an authority: %v", other.Hex(), err)
+	}
+}
+
+func TestRelayDepositPairs(t *testing.T) {
+	bridge := &Chain{Name: "bridge", Id: big.NewInt(1), ContractType: BridgeContract}
+	foreign := &Chain{Name: "foreign", Id: big.NewInt(2), ContractType: ForeignContract}
+	other := &Chain{Name: "other-foreign", Id: big.NewInt(3), ContractType: ForeignContract}
+	home := &Chain{Name: "home", Id: big.NewInt(4), ContractType: HomeContract, Origin: foreign}
+	allChains := []*Chain{bridge, foreign, other, home}
+
+	// none of these are paired, so they fail before anything is sent
+	tests := []struct {
+		name string
+		from *Chain
+		to   *Chain
+	}{
+		{"bridge to home", bridge, home},
+		{"unpaired foreign to home", other, home},
+		{"foreign to bridge", foreign, bridge},
+	}
+	for _, test := range tests {
+		deposit := &DepositEvent{TxHash: common.HexToHash("0xd1"), Value: big.NewInt(10), ToChain: test.to.Id}
+		dest, status, err := relayDeposit(test.from, allChains, deposit)
+		if err == nil || status != DepositFailed || dest != test.to.Name {
+			t.Errorf("%s: got %s on %s, %v, expected the deposit to fail", test.name, status, dest, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// a withdraw or mint tx that has been sent but not mined yet
+type pendingTx struct {
+	Chain    string      `json:"chain"`
+	TxHash   common.Hash `json:"txHash"`
+	Deposit  common.Hash `json:"deposit"` // hash of the deposit the tx relays
+	GasPrice *big.Int    `json:"gasPrice,omitempty"`
+	Sent     time.Time   `json:"sent"`
+}
+
+// txs still pending are saved here when they are sent, so they are tracked again after a restart
+var pendingPath = "log/pending.json"
+
+var pendingMu sync.Mutex
+var pending = map[common.Hash]*pendingTx{}
+var pendingLoaded sync.Once
+var inflight sync.WaitGroup
+
+// trackWithdraw waits in the background for the receipt of a withdraw or mint tx sent on chain for
+// deposit, and counts it as confirmed or failed along with the gas it cost. a tx that is still pending
+// is tracked until it is mined or dropped. if it is dropped or reverts, the deposit is marked as failed,
+// so it is relayed again by a rescan or the admin api; it is not queued again on its own.
+func trackWithdraw(chain *Chain, txHash common.Hash, deposit common.Hash, gasPrice *big.Int) {
+	withdrawsSubmitted.Inc(chain.Name)
+	track(chain, &pendingTx{Chain: chain.Name, TxHash: txHash, Deposit: deposit, GasPrice: gasPrice, Sent: time.Now()})
+}
+
+// track the withdraws sent on chain before the last shutdown that were not mined yet
+func resumeTracking(chain *Chain) {
+	pendingLoaded.Do(loadPending)
+	pendingMu.Lock()
+	txs := []*pendingTx{}
+	for _, tx := range pending {
+		if tx.Chain == chain.Name {
+			txs = append(txs, tx)
+		}
+	}
+	pendingMu.Unlock()
+
+	for _, tx := range txs {
+		logger.Info("tracking withdraw tx %s on %s sent before the last shutdown", tx.TxHash.Hex(), chain.Name)
+		track(chain, tx)
+	}
+}
+
+func track(chain *Chain, tx *pendingTx) {
+	pendingLoaded.Do(loadPending)
+	pendingMu.Lock()
+	pending[tx.TxHash] = tx
+	savePending()
+	pendingMu.Unlock()
+
+	inflight.Add(1)
+	go func() {
+		defer inflight.Done()
+		// a receipt can only appear in a new block, so only ask for it once the head has moved
+		waiter := defaultWaiter
+		waiter.Gate = newBlockGate(chain)
+		l := logger.With(logger.Fields{"chain": chain.Name, "tx": tx.TxHash.Hex(), "deposit": tx.Deposit.Hex()})
+		var receipt *types.Receipt
+		var err error
+		for {
+			receipt, err = waiter.WaitForReceipt(context.Background(), receiptReaderOf(chain), tx.TxHash)
+			// a tx that is still pending, eg. priced too low, can still be mined, so relaying the deposit
+			// again could pay it out twice
+			if err != ErrWaitTimeout {
+				break
+			}
+			l.Warn("withdraw tx still pending after %s, still waiting for it", waiter.Timeout)
+		}
+
+		pendingMu.Lock()
+		delete(pending, tx.TxHash)
+		savePending()
+		pendingMu.Unlock()
+
+		// the deposit can be relayed again, so it no longer has funds reserved for it
+		fail := func(err error) {
+			withdrawsFailed.Inc(chain.Name)
+			store.SetStatus(tx.Deposit, chain.Name, DepositFailed, err)
+			if paysOut(chain) {
+				LiquidityOf(chain).Release(tx.Deposit)
+			}
+		}
+
+		if err != nil {
+			l.Warn("withdraw tx was not mined: %s", err)
+			resetNonce(chain)
+			fail(err)
+			return
+		}
+
+		if tx.GasPrice != nil {
+			spent := new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+			gasSpent.Add(bigFloat(spent), chain.Name)
+		}
+		if receipt.Status == types.ReceiptStatusFailed {
+			logger.Error("withdraw tx %s on %s failed", tx.TxHash.Hex(), chain.Name)
+			fail(errors.New("withdraw tx reverted"))
+			return
+		}
+		withdrawsConfirmed.Inc(chain.Name)
+	}()
+}
+
+// ethclient, asking for a receipt and its tx in one request through rpc
+type batchedReader struct {
+	*ethclient.Client
+	rpc *RpcClient
+}
+
+func (r batchedReader) LookupTx(ctx context.Context, txHash common.Hash) (*types.Receipt, bool, error) {
+	return r.rpc.LookupTx(ctx, txHash)
+}
+
+// what the receipts of txs sent on chain are asked for with; over http, a poll is a single request
+func receiptReaderOf(chain *Chain) receiptReader {
+	if chain.Rpc != nil {
+		return batchedReader{chain.Client, chain.Rpc}
+	}
+	return chain.Client
+}
+
+// WaitForPending waits up to timeout for the withdraws that have been sent to be mined, and returns
+// the number still pending. those are left in log/pending.json and tracked again on the next start.
+func WaitForPending(timeout time.Duration) int {
+	done := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	for _, tx := range pending {
+		logger.Warn("withdraw tx %s on %s is still pending", tx.TxHash.Hex(), tx.Chain)
+	}
+	return len(pending)
+}
+
+func loadPending() {
+	data, err := ioutil.ReadFile(pendingPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("could not load pending txs: %s", err)
+		}
+		return
+	}
+	txs := []*pendingTx{}
+	err = json.Unmarshal(data, &txs)
+	if err != nil {
+		logger.Error("could not load pending txs: %s", err)
+		return
+	}
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	for _, tx := range txs {
+		pending[tx.TxHash] = tx
+	}
+}
+
+// write the pending txs to pendingPath; pendingMu must be held
+func savePending() {
+	txs := make([]*pendingTx, 0, len(pending))
+	for _, tx := range pending {
+		txs = append(txs, tx)
+	}
+	data, err := json.Marshal(txs)
+	if err == nil {
+		err = ioutil.WriteFile(pendingPath+".tmp", data, 0644)
+	}
+	if err == nil {
+		err = os.Rename(pendingPath+".tmp", pendingPath)
+	}
+	if err != nil {
+		logger.Error("could not save pending txs: %s", err)
+	}
+}
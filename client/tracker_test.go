@@ -0,0 +1,103 @@
+package client
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPendingSaved(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultPath := pendingPath
+	pendingPath = filepath.Join(dir, "pending.json")
+	defer func() {
+		pendingPath = defaultPath
+		pending = map[common.Hash]*pendingTx{}
+	}()
+
+	hash := common.HexToHash("0xa")
+	pendingMu.Lock()
+	pending = map[common.Hash]*pendingTx{hash: {Chain: "kovan", TxHash: hash, GasPrice: big.NewInt(10), Sent: time.Now()}}
+	savePending()
+	pending = map[common.Hash]*pendingTx{}
+	pendingMu.Unlock()
+
+	// txs still pending at shutdown are loaded again on the next start
+	loadPending()
+	tx, ok := pending[hash]
+	if !ok || tx.Chain != "kovan" || tx.GasPrice.Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("got %v after loading, expected pending tx on kovan", tx)
+	}
+	if n := WaitForPending(time.Millisecond); n != 1 {
+		t.Fatalf("got %d pending txs, expected 1", n)
+	}
+}
+
+func TestTrackAfterTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defaultPath, waiter := pendingPath, defaultWaiter
+	pendingPath = filepath.Join(dir, "pending.json")
+	defaultWaiter = Waiter{Interval: 5 * time.Millisecond, MaxInterval: 5 * time.Millisecond, Timeout: 20 * time.Millisecond, NotFoundAfter: time.Minute}
+	defer func() {
+		pendingPath, defaultWaiter = defaultPath, waiter
+		store = &Store{size: defaultStoreSize, deposits: make(map[common.Hash]*DepositRecord)}
+	}()
+
+	node := newBridgeNode(50)
+	chain, closeNode := testChain(t, "track-timeout", 1, node)
+	defer closeNode()
+	key, _ := crypto.GenerateKey()
+	tx, _ := types.SignTx(types.NewTransaction(0, *chain.Contract, big.NewInt(0), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	deposit := common.HexToHash("0xd1")
+	node.update(func() { node.pending[tx.Hash()] = tx })
+	store.Seen(chain, &DepositEvent{TxHash: deposit, Value: big.NewInt(10), ToChain: big.NewInt(2)})
+	store.SetStatus(deposit, chain.Name, DepositRelayed, nil)
+
+	trackWithdraw(chain, tx.Hash(), deposit, big.NewInt(1))
+
+	// still pending after several timeouts, so the deposit is not failed
+	time.Sleep(100 * time.Millisecond)
+	if r := store.Get(deposit); r.Status != DepositRelayed {
+		t.Fatalf("got status %s while the tx is pending, expected %s", r.Status, DepositRelayed)
+	}
+
+	// mined in the next block
+	node.update(func() {
+		node.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), BlockNumber: big.NewInt(51), Logs: []*types.Log{}}
+	})
+	// the receipt is asked for once there is a new block; a poll can be cut short by the timeout, so keep adding blocks
+	for block := int64(51); block < 200; block++ {
+		pendingMu.Lock()
+		_, ok := pending[tx.Hash()]
+		pendingMu.Unlock()
+		if !ok {
+			break
+		}
+		headsMu.Lock()
+		heads[chain.Name] = &cachedHead{number: big.NewInt(block), updated: time.Now()}
+		headsMu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := WaitForPending(time.Second); n != 0 {
+		t.Fatalf("got %d pending txs after the tx was mined, expected none", n)
+	}
+	if r := store.Get(deposit); r.Status != DepositRelayed {
+		t.Fatalf("got status %s after the tx was mined, expected %s", r.Status, DepositRelayed)
+	}
+}
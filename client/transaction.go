@@ -1,47 +1,91 @@
 package client
 
 import (
-	"encoding/hex"
 	"context"
+	"encoding/hex"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ChainSafe/ChainBridge/logger"
 )
 
+// gas limit of every tx sent
+const gasLimit = 4600000
+
 // generate the 4-byte identifier from a function signature
-func generateSignature(sig string) (string) {
+func generateSignature(sig string) string {
 	bytes := []byte(sig)
 	hash := crypto.Keccak256(bytes)
 	hex := hex.EncodeToString(hash)
 	return hex[0:8] // first 4 bytes only
 }
 
-// sign a message using chain.From account
+// sign a message using the signer of chain
 func SignMessage(chain *Chain, msg []byte) ([]byte, error) {
-	from := new(accounts.Account)
-	from.Address = *chain.From
-	msg, err := keys.SignHashWithPassphrase(*from, chain.Password, msg)
+	signer, err := signerOf(chain)
 	if err != nil {
 		return nil, err
-	} else { return msg, nil }
+	}
+	msg, err = signer.SignHash(msg)
+	if err != nil {
+		return nil, err
+	} else {
+		return msg, nil
+	}
+}
+
+var sendLocksMu sync.Mutex
+var sendLocks = map[string]*sync.Mutex{}
+
+// the lock held while sending a tx on chain
+func sendLock(chain *Chain) *sync.Mutex {
+	sendLocksMu.Lock()
+	defer sendLocksMu.Unlock()
+	lock, ok := sendLocks[chain.Name]
+	if !ok {
+		lock = new(sync.Mutex)
+		sendLocks[chain.Name] = lock
+	}
+	return lock
 }
 
 // send a tx to chain with calldata
 func SendTx(chain *Chain, value *big.Int, data []byte) (common.Hash, error) {
+	err := checkCanPayGas(chain)
+	if err != nil {
+		logger.Error("could not send tx: %s", err)
+		return *new(common.Hash), err
+	}
+
+	// txs are sent one at a time on each chain, so they do not get the same nonce
+	lock := sendLock(chain)
+	lock.Lock()
+	defer lock.Unlock()
+
 	client := chain.Client
-	from := new(accounts.Account)
-	from.Address = *chain.From
 
+	// a node behind a load balancer may not have seen the last tx sent yet, so the nonce after it is
+	// kept as well, and the higher of the two used
 	nonce, err := client.PendingNonceAt(context.Background(), *chain.From)
-	chain.Nonce = nonce
+	if err != nil {
+		logger.Error("could not get nonce: %s", err)
+		return *new(common.Hash), err
+	}
+	if chain.Nonce > nonce {
+		nonce = chain.Nonce
+	}
 
-	tx := types.NewTransaction(chain.Nonce, *chain.Contract, value, uint64(4600000), chain.GasPrice, data)
-	txSigned, err := keys.SignTxWithPassphrase(*from, chain.Password, tx, chain.Id)
+	tx := types.NewTransaction(nonce, *chain.Contract, value, uint64(gasLimit), chain.GasPrice, data)
+	signer, err := signerOf(chain)
+	if err != nil {
+		logger.Error("could not sign tx: %s", err)
+		return *new(common.Hash), err
+	}
+	txSigned, err := signer.SignTx(tx, chain.Id)
 	if err != nil {
 		logger.Error("could not sign tx: %s", err)
 		return *new(common.Hash), err
@@ -53,16 +97,26 @@ func SendTx(chain *Chain, value *big.Int, data []byte) (common.Hash, error) {
 		logger.Error("could not send tx: %s", err)
 		return *new(common.Hash), err
 	}
+	chain.Nonce = nonce + 1
 
 	return txHash, nil
 }
 
+// forget the nonce after the last tx sent on chain, eg. once a tx has been dropped, so the next tx
+// takes the place of the dropped one rather than waiting behind it forever
+func resetNonce(chain *Chain) {
+	lock := sendLock(chain)
+	lock.Lock()
+	defer lock.Unlock()
+	chain.Nonce = 0
+}
+
 func AddAuthority(chain *Chain, address string) error {
 	dataStr := generateSignature("addAuthority(address)") + padTo32Bytes(address[2:]) // setbridge function signature + contract addr
 	data, err := hex.DecodeString(dataStr)
 	if err != nil {
 		return err
-	} 
+	}
 
 	txHash, err := SendTx(chain, big.NewInt(0), data)
 	if err != nil {
@@ -80,12 +134,12 @@ func Deposit(chain *Chain, value *big.Int, id string) error {
 	data, err := hex.DecodeString(dataStr)
 	if err != nil {
 		return err
-	} 
+	}
 
 	txHash, err := SendTx(chain, value, data)
 	if err != nil {
 		return err
-	}	
+	}
 
 	logger.Info("sending tx %s to deposit on %s...", txHash.Hex(), chain.Name)
 	return nil
@@ -95,7 +149,7 @@ func PayBridge(chain *Chain, value *big.Int) error {
 	txHash, err := SendTx(chain, value, []byte{})
 	if err != nil {
 		return err
-	}	
+	}
 
 	logger.Info("sending tx %s to pay bridge on %s...", txHash.Hex(), chain.Name)
 	return nil
@@ -107,12 +161,12 @@ func WithdrawTo(chain *Chain, value *big.Int, id string) error {
 	data, err := hex.DecodeString(dataStr)
 	if err != nil {
 		return err
-	} 
+	}
 
 	txHash, err := SendTx(chain, value, data)
 	if err != nil {
 		return err
-	}	
+	}
 
 	logger.Info("sending tx %s to deposit on %s...", txHash.Hex(), chain.Name)
 	return nil
@@ -120,18 +174,57 @@ func WithdrawTo(chain *Chain, value *big.Int, id string) error {
 
 func Withdraw(chain *Chain, withdrawal *Withdrawal) error {
 	w := setWithdrawalData(withdrawal)
-	dataStr := "4250a6f3" + w.Data 
+	dataStr := "4250a6f3" + w.Data
 	data, err := hex.DecodeString(dataStr)
 	if err != nil {
 		return err
-	} 
+	}
 
 	txHash, err := SendTx(chain, big.NewInt(0), data)
 	if err != nil {
+		withdrawsFailed.Inc(chain.Name)
 		return err
-	}	
+	}
 
 	logger.Info("sending tx %s to withdraw on %s...", txHash.Hex(), chain.Name)
+	trackWithdraw(chain, txHash, common.HexToHash(withdrawal.TxHash), chain.GasPrice)
+	return nil
+}
+
+// mint wrapped tokens on chain for a deposit on the origin chain; takes the same arguments as Withdraw
+func Mint(chain *Chain, withdrawal *Withdrawal) error {
+	w := setWithdrawalData(withdrawal)
+	dataStr := generateSignature("mint(address,uint256,uint256,bytes32)") + w.Data
+	data, err := hex.DecodeString(dataStr)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := SendTx(chain, big.NewInt(0), data)
+	if err != nil {
+		withdrawsFailed.Inc(chain.Name)
+		return err
+	}
+
+	logger.Info("sending tx %s to mint on %s...", txHash.Hex(), chain.Name)
+	trackWithdraw(chain, txHash, common.HexToHash(withdrawal.TxHash), chain.GasPrice)
+	return nil
+}
+
+// burn wrapped tokens on chain to release the locked ether on the chain with id, in hexadecimal
+func Burn(chain *Chain, value *big.Int, id string) error {
+	dataStr := generateSignature("burn(address,uint256,uint256)") + padTo32Bytes(chain.From.Hex()[2:]) + padBigTo32Bytes(value) + padTo32Bytes(id)
+	data, err := hex.DecodeString(dataStr)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := SendTx(chain, big.NewInt(0), data)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("sending tx %s to burn on %s...", txHash.Hex(), chain.Name)
 	return nil
 }
 
@@ -142,13 +235,17 @@ func FundBridge(chain *Chain, value *big.Int) error {
 	data, err := hex.DecodeString("c9c0909f") //fund me function sig
 	if err != nil {
 		return err
-	} 
+	}
+	// the home contract is funded through its fallback function
+	if chain.ContractType == HomeContract {
+		data = []byte{}
+	}
 
 	txHash, err := SendTx(chain, weiValue, data)
 	if err != nil {
 		return err
-	}	
+	}
 
 	logger.Info("sending tx %s to fund bridge on %s with value %s...", txHash.Hex(), chain.Name, value.String())
 	return nil
-}
\ No newline at end of file
+}
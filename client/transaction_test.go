@@ -0,0 +1,65 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signs with a key in memory
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s keySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s keySigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainId), s.key)
+}
+
+func (s keySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+func TestSendTxNonce(t *testing.T) {
+	node := newBridgeNode(50)
+	chain, closeNode := testChain(t, "send-nonce", 1, node)
+	defer closeNode()
+	key, _ := crypto.GenerateKey()
+	chain.Signer = keySigner{key}
+	chain.GasPrice = big.NewInt(1)
+
+	// the node has not seen the first tx yet when the second is sent, so its pending nonce is behind
+	node.update(func() { node.nonce = 3 })
+	for i := 0; i < 2; i++ {
+		if _, err := SendTx(chain, big.NewInt(0), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the node is ahead, eg. after a tx was sent from the account by hand
+	node.update(func() { node.nonce = 9 })
+	if _, err := SendTx(chain, big.NewInt(0), nil); err != nil {
+		t.Fatal(err)
+	}
+	// that tx is dropped, so the node's pending nonce is still 9, and used again
+	resetNonce(chain)
+	if _, err := SendTx(chain, big.NewInt(0), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []uint64{3, 4, 9, 9}
+	if len(node.sent) != len(expected) {
+		t.Fatalf("sent %d txs, expected %d", len(node.sent), len(expected))
+	}
+	for i, nonce := range expected {
+		if node.sent[i].Nonce() != nonce {
+			t.Errorf("tx %d -- got nonce %d expected %d", i, node.sent[i].Nonce(), nonce)
+		}
+	}
+}
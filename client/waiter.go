@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// outcomes of waiting for a tx other than it being mined
+var (
+	ErrTxNotFound  = errors.New("tx not found: the node has never seen it")
+	ErrTxDropped   = errors.New("tx dropped: the node saw it pending, then lost it")
+	ErrWaitTimeout = errors.New("timed out waiting")
+)
+
+// Waiter polls for something to happen, backing off exponentially between polls
+type Waiter struct {
+	Interval    time.Duration // time between the first polls
+	MaxInterval time.Duration // the time between polls doubles up to this
+	Timeout     time.Duration // give up after this long; 0 waits until the context is cancelled
+	// give up on a tx the node has never seen after this long; a tx that was just sent may not
+	// have reached every node behind a load balancer yet
+	NotFoundAfter time.Duration
+	// if set, a poll is skipped unless it returns true, eg. while there is no new block
+	Gate func(ctx context.Context) bool
+}
+
+var defaultWaiter = Waiter{
+	Interval:      2 * time.Second,
+	MaxInterval:   30 * time.Second,
+	Timeout:       10 * time.Minute,
+	NotFoundAfter: 2 * time.Minute,
+}
+
+// the calls WaitForReceipt makes, so it can be tested without a node
+type receiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// a receiptReader that can ask for the receipt and the tx in one request, as RpcClient.LookupTx does
+type txLookup interface {
+	LookupTx(ctx context.Context, txHash common.Hash) (*types.Receipt, bool, error)
+}
+
+// the receipt of txHash if it has been mined, and whether the node knows about the tx
+func lookupTx(ctx context.Context, client receiptReader, txHash common.Hash) (*types.Receipt, bool, error) {
+	if l, ok := client.(txLookup); ok {
+		return l.LookupTx(ctx, txHash)
+	}
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err == nil && receipt != nil {
+		return receipt, true, nil
+	}
+	if err != nil && err != ethereum.NotFound {
+		return nil, false, err
+	}
+	_, _, err = client.TransactionByHash(ctx, txHash)
+	if err == ethereum.NotFound {
+		return nil, false, nil
+	}
+	return nil, err == nil, err
+}
+
+// Poll calls check until it returns true or an error, ctx is cancelled, or w.Timeout passes,
+// in which case it returns ctx.Err() or ErrWaitTimeout
+func (w Waiter) Poll(ctx context.Context, check func(ctx context.Context) (bool, error)) error {
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	interval := w.Interval
+	for {
+		if w.Gate == nil || w.Gate(ctx) {
+			done, err := check(ctx)
+			if err != nil || done {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return ErrWaitTimeout
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > w.MaxInterval {
+			interval = w.MaxInterval
+		}
+	}
+}
+
+// WaitForReceipt waits for the receipt of txHash. it returns ErrTxNotFound if the node has not seen the
+// tx after w.NotFoundAfter, ErrTxDropped if it was pending and then disappeared, and ErrWaitTimeout if it
+// is still pending after w.Timeout. errors from the node are retried until then.
+func (w Waiter) WaitForReceipt(ctx context.Context, client receiptReader, txHash common.Hash) (*types.Receipt, error) {
+	start := time.Now()
+	seen := false
+	var receipt *types.Receipt
+
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		r, known, err := lookupTx(ctx, client, txHash)
+		if err != nil {
+			logger.Debug("could not get receipt of tx %s, retrying: %s", txHash.Hex(), err)
+			return false, nil
+		}
+		if r != nil {
+			receipt = r
+			return true, nil
+		}
+
+		if !known {
+			if seen {
+				// it may have been mined since the receipt was asked for
+				r, err := client.TransactionReceipt(ctx, txHash)
+				if err == nil && r != nil {
+					receipt = r
+					return true, nil
+				}
+				return false, ErrTxDropped
+			}
+			if time.Since(start) >= w.NotFoundAfter {
+				return false, ErrTxNotFound
+			}
+			return false, nil
+		}
+		seen = true
+		return false, nil
+	})
+	return receipt, err
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// a node that knows about a tx for some polls and has a receipt for it after others
+type fakeNode struct {
+	polls   int
+	known   func(poll int) bool
+	minedAt int // poll the receipt appears at; 0 is never
+	flakyAt int // poll the node returns an error at; 0 is never
+}
+
+func (n *fakeNode) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	n.polls++
+	if n.flakyAt != 0 && n.polls == n.flakyAt {
+		return nil, errors.New("connection refused")
+	}
+	if n.minedAt != 0 && n.polls >= n.minedAt {
+		return &types.Receipt{Status: types.ReceiptStatusSuccessful}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (n *fakeNode) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	if n.known(n.polls) {
+		return nil, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func TestWaitForReceipt(t *testing.T) {
+	w := Waiter{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Timeout: time.Second, NotFoundAfter: 20 * time.Millisecond}
+	always := func(int) bool { return true }
+
+	tests := []struct {
+		name string
+		node *fakeNode
+		err  error
+	}{
+		{"mined", &fakeNode{known: always, minedAt: 3}, nil},
+		{"node errors are retried", &fakeNode{known: always, minedAt: 3, flakyAt: 2}, nil},
+		{"never seen", &fakeNode{known: func(int) bool { return false }}, ErrTxNotFound},
+		{"dropped", &fakeNode{known: func(poll int) bool { return poll < 3 }}, ErrTxDropped},
+	}
+	for _, test := range tests {
+		receipt, err := w.WaitForReceipt(context.Background(), test.node, common.HexToHash("0xa"))
+		if err != test.err {
+			t.Errorf("%s: got error %v, expected %v", test.name, err, test.err)
+		}
+		if err == nil && receipt == nil {
+			t.Errorf("%s: got no receipt", test.name)
+		}
+	}
+
+	// still pending
+	w.Timeout = 20 * time.Millisecond
+	_, err := w.WaitForReceipt(context.Background(), &fakeNode{known: always}, common.HexToHash("0xa"))
+	if err != ErrWaitTimeout {
+		t.Errorf("got error %v for pending tx, expected %v", err, ErrWaitTimeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = w.WaitForReceipt(ctx, &fakeNode{known: always}, common.HexToHash("0xa"))
+	if err != context.Canceled {
+		t.Errorf("got error %v after cancelling, expected %v", err, context.Canceled)
+	}
+}
+
+func TestWaiterGate(t *testing.T) {
+	open := false
+	w := Waiter{Interval: time.Millisecond, MaxInterval: time.Millisecond, Timeout: 20 * time.Millisecond, NotFoundAfter: time.Second}
+	w.Gate = func(ctx context.Context) bool { return open }
+
+	// the node is not asked while the gate is closed, eg. while there is no new block
+	node := &fakeNode{known: func(int) bool { return true }, minedAt: 1}
+	_, err := w.WaitForReceipt(context.Background(), node, common.HexToHash("0xa"))
+	if err != ErrWaitTimeout || node.polls != 0 {
+		t.Fatalf("got %v after %d polls with the gate closed, expected a timeout and no polls", err, node.polls)
+	}
+
+	open = true
+	receipt, err := w.WaitForReceipt(context.Background(), node, common.HexToHash("0xa"))
+	if err != nil || receipt == nil {
+		t.Fatalf("got %v with the gate open, expected the receipt", err)
+	}
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/logger"
+)
+
+// states of a relayer account
+const (
+	WalletOk       = "ok"
+	WalletWarning  = "warning"  // can afford fewer than the chain's walletWarning withdraws
+	WalletCritical = "critical" // can afford fewer than the chain's walletCritical withdraws
+)
+
+// default number of withdraws below which a relayer account is in the warning or critical state
+const (
+	DefaultWalletWarning  = 20
+	DefaultWalletCritical = 5
+)
+
+// Wallet is the gas balance of the relayer account on a chain, as of the last check
+type Wallet struct {
+	Chain     string
+	Balance   *big.Int
+	GasPrice  *big.Int
+	TxCost    *big.Int // the most gas a withdraw can cost: gas limit * gas price
+	Withdraws uint64   // number of withdraws the balance can still pay gas for
+	State     string
+	Updated   time.Time
+}
+
+var walletsMu sync.Mutex
+var wallets = map[string]*Wallet{}
+
+// Wallets returns the last checked state of the relayer account on every chain
+func Wallets() []Wallet {
+	walletsMu.Lock()
+	defer walletsMu.Unlock()
+	out := []Wallet{}
+	for _, w := range wallets {
+		out = append(out, *w)
+	}
+	return out
+}
+
+func walletOf(chain *Chain) *Wallet {
+	walletsMu.Lock()
+	defer walletsMu.Unlock()
+	w, ok := wallets[chain.Name]
+	if !ok {
+		return nil
+	}
+	last := *w
+	return &last
+}
+
+// the number of affordable withdraws below which the account is in the warning and critical states
+func walletThresholds(chain *Chain) (uint64, uint64) {
+	warning, critical := chain.WalletWarning, chain.WalletCritical
+	if warning == 0 {
+		warning = DefaultWalletWarning
+	}
+	if critical == 0 {
+		critical = DefaultWalletCritical
+	}
+	return warning, critical
+}
+
+// state of an account that can pay gas for the given number of withdraws
+func walletState(withdraws, warning, critical uint64) string {
+	switch {
+	case withdraws < critical:
+		return WalletCritical
+	case withdraws < warning:
+		return WalletWarning
+	}
+	return WalletOk
+}
+
+// the gas price txs are sent with on chain: gasPrice from the config, or the node's suggestion if not set
+func gasPrice(chain *Chain) (*big.Int, error) {
+	if chain.GasPrice != nil {
+		return chain.GasPrice, nil
+	}
+	return chain.Client.SuggestGasPrice(context.Background())
+}
+
+// CheckWallet reads the balance of the relayer account on chain, works out how many withdraws it can
+// still pay gas for and logs and alerts when the account enters or leaves the warning or critical state
+func CheckWallet(chain *Chain) (*Wallet, error) {
+	ctx := context.Background()
+	balance, err := chain.Client.BalanceAt(ctx, *chain.From, nil)
+	if err != nil {
+		return nil, err
+	}
+	price, err := gasPrice(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Wallet{
+		Chain:    chain.Name,
+		Balance:  balance,
+		GasPrice: price,
+		TxCost:   new(big.Int).Mul(price, new(big.Int).SetUint64(gasLimit)),
+		Updated:  time.Now(),
+	}
+	if w.TxCost.Sign() > 0 {
+		w.Withdraws = new(big.Int).Div(balance, w.TxCost).Uint64()
+	} else {
+		w.Withdraws = ^uint64(0)
+	}
+	warning, critical := walletThresholds(chain)
+	w.State = walletState(w.Withdraws, warning, critical)
+
+	relayerBalance.Set(bigFloat(balance), chain.Name)
+	relayerWithdraws.Set(float64(w.Withdraws), chain.Name)
+	for _, state := range []string{WalletOk, WalletWarning, WalletCritical} {
+		if state == w.State {
+			relayerState.Set(1, chain.Name, state)
+		} else {
+			relayerState.Set(0, chain.Name, state)
+		}
+	}
+
+	walletsMu.Lock()
+	prev, ok := wallets[chain.Name]
+	wallets[chain.Name] = w
+	walletsMu.Unlock()
+
+	if ok && prev.State == w.State {
+		return w, nil
+	}
+	switch w.State {
+	case WalletCritical:
+		notify(AlertCritical, chain, "relayer account %s has %s wei, enough gas for %d withdraws at %s wei per gas", chain.From.Hex(), balance, w.Withdraws, price)
+	case WalletWarning:
+		notify(AlertWarning, chain, "relayer account %s has %s wei, enough gas for %d withdraws at %s wei per gas", chain.From.Hex(), balance, w.Withdraws, price)
+	default:
+		if ok {
+			notify(AlertResolved, chain, "relayer account %s has %s wei, enough gas for %d withdraws", chain.From.Hex(), balance, w.Withdraws)
+		} else {
+			logger.Info("relayer account %s on %s has %s wei, enough gas for %d withdraws", chain.From.Hex(), chain.Name, balance, w.Withdraws)
+		}
+	}
+	return w, nil
+}
+
+// returns an error if the relayer account on chain is known not to have enough for the gas of a tx,
+// rather than sending a tx the node will reject
+func checkCanPayGas(chain *Chain) error {
+	w := walletOf(chain)
+	if w == nil || w.Balance.Cmp(w.TxCost) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("relayer account %s on %s has %s wei, needs %s wei to pay for gas", chain.From.Hex(), chain.Name, w.Balance, w.TxCost)
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWalletState(t *testing.T) {
+	cases := []struct {
+		withdraws uint64
+		expected  string
+	}{
+		{0, WalletCritical},
+		{4, WalletCritical},
+		{5, WalletWarning},
+		{19, WalletWarning},
+		{20, WalletOk},
+		{1000, WalletOk},
+	}
+
+	warning, critical := walletThresholds(&Chain{})
+	for _, c := range cases {
+		actual := walletState(c.withdraws, warning, critical)
+		if actual != c.expected {
+			t.Errorf("%d withdraws -- got: %s expected: %s", c.withdraws, actual, c.expected)
+		}
+	}
+}
+
+func TestCheckCanPayGas(t *testing.T) {
+	from := common.HexToAddress("0xc7756f27d7f8c2e45d790bfd340a4ab73b4a6e95")
+	chain := &Chain{Name: "gas test", From: &from}
+	if err := checkCanPayGas(chain); err != nil {
+		t.Fatalf("unchecked account should be allowed to send: %s", err)
+	}
+
+	wallets[chain.Name] = &Wallet{Balance: big.NewInt(99), TxCost: big.NewInt(100)}
+	defer delete(wallets, chain.Name)
+	if err := checkCanPayGas(chain); err == nil {
+		t.Fatal("expected an error for an account that cannot pay for gas")
+	}
+}
@@ -0,0 +1,843 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ChainSafe/ChainBridge/client"
+)
+
+// a problem with a field of the config; chain is empty for fields outside networks
+type configError struct {
+	chain string
+	field string
+	msg   string
+}
+
+func (e *configError) Error() string {
+	if e.chain != "" && e.field == "" {
+		return fmt.Sprintf("networks.%s: %s", e.chain, e.msg)
+	}
+	if e.chain != "" {
+		return fmt.Sprintf("networks.%s.%s: %s", e.chain, e.field, e.msg)
+	}
+	return fmt.Sprintf("%s: %s", e.field, e.msg)
+}
+
+// every problem found in a config
+type configErrors []*configError
+
+func (errs configErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = "  " + err.Error()
+	}
+	return fmt.Sprintf("%d problems in the config:\n%s", len(errs), strings.Join(lines, "\n"))
+}
+
+// loadConfig reads the config at path and checks every field, returning every problem found.
+// the config is a json, yaml or toml file, or a directory of them; see readConfigTree.
+// ${VAR} in any string is replaced with the environment variable VAR, and then any field can be
+// overridden with an environment variable, eg. CHAINBRIDGE_NETWORKS_KOVAN_URL.
+// unknown fields are problems too, so a misspelt field is not silently ignored
+func loadConfig(path string) (*Config, configErrors, error) {
+	tree, err := readConfigTree(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = interpolate(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = applyEnvOverrides(tree, os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+	// encoding/json matches field names in any case and stops at the first value it cannot decode,
+	// so check every field first, leaving out the ones with problems so the rest can be decoded
+	errs := configErrors{}
+	checkFields(tree, reflect.TypeOf(Config{}), "", &errs)
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := new(Config)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not unmarshal config: %s", err)
+	}
+	// a field left out is already a problem, so it is not also reported as missing
+	for _, err := range config.validate() {
+		if !errs.has(err.chain, err.field) {
+			errs = append(errs, err)
+		}
+	}
+	return config, errs, nil
+}
+
+// readConfigTree reads the config at path into maps, slices and values. a directory is read as
+// every config file in it in name order, each one adding to or overriding the ones before it,
+// and every file in its networks/ directory as a single chain named after the file,
+// eg. networks/kovan.yaml is networks.kovan
+func readConfigTree(path string) (map[string]interface{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readConfigFile(path)
+	}
+
+	tree := map[string]interface{}{}
+	files, err := configFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		part, err := readConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		merge(tree, part)
+	}
+
+	networksDir := filepath.Join(path, "networks")
+	if _, err := os.Stat(networksDir); err == nil {
+		files, err := configFiles(networksDir)
+		if err != nil {
+			return nil, err
+		}
+		networks, _ := tree["networks"].(map[string]interface{})
+		if networks == nil {
+			networks = map[string]interface{}{}
+			tree["networks"] = networks
+		}
+		for _, file := range files {
+			chain, err := readConfigFile(file)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			if existing, ok := networks[name].(map[string]interface{}); ok {
+				merge(existing, chain)
+			} else {
+				networks[name] = chain
+			}
+		}
+	}
+	return tree, nil
+}
+
+// the config files in dir, in name order
+func configFiles(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml", ".toml":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// read a json, yaml or toml file, by its extension
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := map[string]interface{}{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		var parsed interface{}
+		err = yaml.Unmarshal(data, &parsed)
+		if err == nil {
+			var normalized interface{}
+			normalized, err = normalize("", parsed)
+			m, ok := normalized.(map[string]interface{})
+			if err == nil && !ok && parsed != nil {
+				err = fmt.Errorf("expected a map at the top level")
+			}
+			if ok {
+				tree = m
+			}
+		}
+	case ".toml":
+		_, err = toml.Decode(string(data), &tree)
+		if err == nil {
+			_, err = normalize("", tree)
+		}
+	default:
+		// numbers are kept as they are written, since amounts of wei do not fit in a float64
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&tree)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+	return tree, nil
+}
+
+// convert what the yaml and toml parsers return to what encoding/json can marshal; key is where value is
+// in the file, for errors
+func normalize(key string, value interface{}) (interface{}, error) {
+	var err error
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			name := fmt.Sprint(k)
+			if m[name], err = normalize(joinKey(key, name), value); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case map[string]interface{}:
+		for k, value := range v {
+			if v[k], err = normalize(joinKey(key, k), value); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []map[string]interface{}:
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			if s[i], err = normalize(fmt.Sprintf("%s[%d]", key, i), value); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	case []interface{}:
+		for i, value := range v {
+			if v[i], err = normalize(fmt.Sprintf("%s[%d]", key, i), value); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case float64:
+		// yaml parses integers too big for 64 bits as floats, which only hold integers up to 2^53 exactly;
+		// anything bigger may have been rounded, so it has to be written as a string
+		if v == math.Trunc(v) && math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("%s is too big to be read exactly; quote it, eg. \"%s\"", key, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	}
+	return value, nil
+}
+
+// the key of a value in a map at parent
+func joinKey(parent string, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// merge src into dst; maps in both are merged, and anything else in src replaces what is in dst
+func merge(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcOk := value.(map[string]interface{})
+		dstMap, dstOk := dst[key].(map[string]interface{})
+		if srcOk && dstOk {
+			merge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolate replaces ${VAR} in every string of the tree with the environment variable VAR,
+// so secrets do not have to be in the config. every variable that is not set is an error
+func interpolate(tree map[string]interface{}) error {
+	missing := []string{}
+	var walk func(value interface{}) interface{}
+	walk = func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			return envReference.ReplaceAllStringFunc(v, func(ref string) string {
+				name := envReference.FindStringSubmatch(ref)[1]
+				env, ok := os.LookupEnv(name)
+				if !ok && !contains(missing, name) {
+					missing = append(missing, name)
+				}
+				return env
+			})
+		case map[string]interface{}:
+			for key, value := range v {
+				v[key] = walk(value)
+			}
+		case []interface{}:
+			for i, value := range v {
+				v[i] = walk(value)
+			}
+		}
+		return value
+	}
+	walk(tree)
+
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("environment variables used in the config are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// environment variables overriding fields of the config start with this,
+// followed by the path of the field in upper case, separated by _
+const envOverridePrefix = "CHAINBRIDGE_"
+
+// applyEnvOverrides sets the fields of the tree named by environment variables, eg.
+// CHAINBRIDGE_NETWORKS_KOVAN_URL sets networks.kovan.url and CHAINBRIDGE_PAIRS_0_TO sets pairs[0].to.
+// only variables starting with a section of the config are looked at, so CHAINBRIDGE_PASSWORD is not
+func applyEnvOverrides(tree map[string]interface{}, environ []string) error {
+	sections := map[string]bool{}
+	for _, name := range jsonFields(reflect.TypeOf(Config{})) {
+		sections[strings.ToUpper(name)] = true
+	}
+
+	overrides := []string{}
+	for _, kv := range environ {
+		if strings.HasPrefix(kv, envOverridePrefix) {
+			overrides = append(overrides, kv)
+		}
+	}
+	// so the result does not depend on the order of the environment
+	sort.Strings(overrides)
+
+	for _, kv := range overrides {
+		parts := strings.SplitN(kv, "=", 2)
+		segments := strings.Split(parts[0][len(envOverridePrefix):], "_")
+		if !sections[segments[0]] {
+			continue
+		}
+		err := override(tree, reflect.TypeOf(Config{}), segments, parts[1])
+		if err != nil {
+			return fmt.Errorf("%s: %s", parts[0], err)
+		}
+	}
+	return nil
+}
+
+// set the field at segments of node, which holds a value of type t, to value
+func override(node interface{}, t reflect.Type, segments []string, value string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("not an object in the config")
+		}
+		for _, field := range jsonFieldsOf(t) {
+			if strings.ToUpper(field.name) != segments[0] {
+				continue
+			}
+			return overrideIn(m, field.name, field.typ, segments[1:], value)
+		}
+		return fmt.Errorf("no field %s", strings.ToLower(segments[0]))
+
+	case reflect.Map:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("not an object in the config")
+		}
+		// keys, eg. chain names, can have _ or - in them, so match the longest one
+		best, bestLen := "", 0
+		for key := range m {
+			keySegments := strings.Split(strings.ToUpper(strings.Replace(key, "-", "_", -1)), "_")
+			if len(keySegments) > bestLen && len(keySegments) <= len(segments) &&
+				strings.Join(keySegments, "_") == strings.Join(segments[:len(keySegments)], "_") {
+				best, bestLen = key, len(keySegments)
+			}
+		}
+		if bestLen == 0 {
+			return fmt.Errorf("nothing in the config matches %s", strings.Join(segments, "_"))
+		}
+		return overrideIn(m, best, t.Elem(), segments[bestLen:], value)
+
+	case reflect.Slice:
+		s, ok := node.([]interface{})
+		if !ok {
+			return fmt.Errorf("not a list in the config")
+		}
+		i, err := strconv.Atoi(segments[0])
+		if err != nil || i < 0 || i >= len(s) {
+			return fmt.Errorf("no item %s in the list", segments[0])
+		}
+		if len(segments) == 1 {
+			parsed, err := envValue(t.Elem(), value)
+			if err != nil {
+				return err
+			}
+			s[i] = parsed
+			return nil
+		}
+		return override(s[i], t.Elem(), segments[1:], value)
+	}
+	return fmt.Errorf("cannot override a field inside a %s", t.Kind())
+}
+
+// set key of m, which holds a value of type t, or the field at segments inside it
+func overrideIn(m map[string]interface{}, key string, t reflect.Type, segments []string, value string) error {
+	if len(segments) == 0 {
+		parsed, err := envValue(t, value)
+		if err != nil {
+			return err
+		}
+		m[key] = parsed
+		return nil
+	}
+	if _, ok := m[key]; !ok {
+		// eg. the rpc policy of a chain that does not have one yet
+		m[key] = map[string]interface{}{}
+	}
+	return override(m[key], t, segments, value)
+}
+
+// the value of an environment variable as the type of the field it sets
+func envValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("expected a number, not %q", value)
+		}
+		return json.Number(value), nil
+	}
+	// eg. a BigInt, which takes a decimal string, or a whole object as json
+	var parsed interface{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if decoder.Decode(&parsed) == nil {
+		return parsed, nil
+	}
+	return value, nil
+}
+
+// checkFields adds a problem to errs for every field of node that is not a field of t, which is the type
+// of node, is in a different case to it, eg. gasprice for gasPrice, or holds a value that t cannot be
+// decoded from. returns false if node itself cannot be decoded, so it can be left out and the rest of
+// the config still decoded
+func checkFields(node interface{}, t reflect.Type, path string, errs *configErrors) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node == nil {
+		return true
+	}
+	// eg. a BigInt, which decodes itself
+	if reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return checkValue(node, t, path, errs)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			errs.add(path, "expected an object")
+			return false
+		}
+		fields := map[string]reflect.Type{}
+		for _, field := range jsonFieldsOf(t) {
+			fields[field.name] = field.typ
+		}
+		for _, key := range sortedKeys(m) {
+			typ, ok := fields[key]
+			if !ok {
+				errs.add(fieldPath(path, key), "unknown field"+similarField(key, fields))
+				delete(m, key)
+				continue
+			}
+			if !checkFields(m[key], typ, fieldPath(path, key), errs) {
+				delete(m, key)
+			}
+		}
+	case reflect.Map:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			errs.add(path, "expected an object")
+			return false
+		}
+		for _, key := range sortedKeys(m) {
+			if !checkFields(m[key], t.Elem(), fieldPath(path, key), errs) {
+				delete(m, key)
+			}
+		}
+	case reflect.Slice:
+		s, ok := node.([]interface{})
+		if !ok {
+			errs.add(path, "expected a list")
+			return false
+		}
+		for i, value := range s {
+			if !checkFields(value, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs) {
+				s[i] = nil
+			}
+		}
+	default:
+		return checkValue(node, t, path, errs)
+	}
+	return true
+}
+
+// checkValue adds a problem to errs and returns false if a value of t cannot be decoded from node
+func checkValue(node interface{}, t reflect.Type, path string, errs *configErrors) bool {
+	data, err := json.Marshal(node)
+	if err == nil {
+		err = json.Unmarshal(data, reflect.New(t).Interface())
+	}
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		errs.add(path, fmt.Sprintf("expected %s, not %s", kindName(t.Kind()), data))
+		return false
+	}
+	if err != nil {
+		errs.add(path, strings.TrimPrefix(err.Error(), "json: "))
+		return false
+	}
+	return true
+}
+
+// how a value of kind is written in a config, for errors
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.String:
+		return "a string"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer of 0 or more"
+	}
+	return "a " + kind.String()
+}
+
+// the field of fields key differs from only in case, as a hint to add to an unknown field
+func similarField(key string, fields map[string]reflect.Type) string {
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return fmt.Sprintf("; did you mean %s?", name)
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// add a problem with the field at path, eg. networks.kovan.gasPrice, which is of the chain kovan
+func (errs *configErrors) add(path string, msg string) {
+	err := &configError{field: path, msg: msg}
+	if parts := strings.SplitN(path, ".", 3); parts[0] == "networks" && len(parts) > 1 {
+		err.chain = parts[1]
+		err.field = ""
+		if len(parts) == 3 {
+			err.field = parts[2]
+		}
+	}
+	*errs = append(*errs, err)
+}
+
+// whether errs has a problem with the field of chain, or the field is inside one that has a problem
+func (errs configErrors) has(chain string, field string) bool {
+	for _, err := range errs {
+		if err.chain == chain && (err.field == "" || err.field == field || strings.HasPrefix(field, err.field+".") || strings.HasPrefix(field, err.field+"[")) {
+			return true
+		}
+	}
+	return false
+}
+
+// the path of key inside path, eg. networks.kovan; the top level of the config if both are empty
+func fieldPath(path string, key string) string {
+	switch {
+	case path == "" && key == "":
+		return "the config"
+	case path == "":
+		return key
+	case key == "":
+		return path
+	}
+	return path + "." + key
+}
+
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+// the fields of struct t as they are named in json
+func jsonFieldsOf(t reflect.Type) []jsonField {
+	fields := []jsonField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{name, f.Type})
+	}
+	return fields
+}
+
+func jsonFields(t reflect.Type) []string {
+	names := []string{}
+	for _, field := range jsonFieldsOf(t) {
+		names = append(names, field.name)
+	}
+	return names
+}
+
+// validate checks every field of the config
+func (c *Config) validate() configErrors {
+	errs := configErrors{}
+	add := func(chain string, field string, format string, args ...interface{}) {
+		errs = append(errs, &configError{chain, field, fmt.Sprintf(format, args...)})
+	}
+
+	if len(c.Chain) == 0 {
+		add("", "networks", "no chains")
+	}
+	names := make([]string, 0, len(c.Chain))
+	for name := range c.Chain {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := map[string]string{}
+	for _, name := range names {
+		chain := c.Chain[name]
+		if chain == nil {
+			add(name, "", "empty")
+			continue
+		}
+		field := func(field string, format string, args ...interface{}) {
+			add(name, field, format, args...)
+		}
+
+		if chain.Id == nil {
+			field("id", "missing")
+		} else if chain.Id.Sign() < 0 {
+			field("id", "must not be negative")
+		} else if other, ok := ids[chain.Id.String()]; ok {
+			field("id", "%s is also the id of %s", chain.Id, other)
+		} else {
+			ids[chain.Id.String()] = name
+		}
+
+		if chain.Url == "" && len(chain.Urls) == 0 {
+			field("url", "missing; set url or urls")
+		}
+		if chain.Url != "" {
+			if err := validNodeUrl(chain.Url); err != nil {
+				field("url", "%s", err)
+			}
+		}
+		for i, endpoint := range chain.Urls {
+			if err := validNodeUrl(endpoint.Url); err != nil {
+				field(fmt.Sprintf("urls[%d].url", i), "%s", err)
+			} else if len(chain.Urls) > 1 && !isHttpUrl(endpoint.Url) {
+				field(fmt.Sprintf("urls[%d].url", i), "only http endpoints can be listed with others")
+			}
+			if endpoint.RateLimit < 0 || endpoint.Burst < 0 {
+				field(fmt.Sprintf("urls[%d]", i), "rateLimit and burst must not be negative")
+			}
+		}
+
+		if err := validAddress(chain.Contract); err != nil {
+			field("contractAddr", "%s", err)
+		}
+		if err := validAddress(chain.From); err != nil {
+			field("from", "%s", err)
+		}
+
+		if chain.GasPrice == nil {
+			field("gasPrice", "missing")
+		} else if chain.GasPrice.Sign() <= 0 {
+			field("gasPrice", "must be positive")
+		}
+		if chain.MinBalance != nil && chain.MinBalance.Sign() < 0 {
+			field("minBalance", "must not be negative")
+		}
+		if chain.WalletWarning != 0 && chain.WalletCritical > chain.WalletWarning {
+			field("walletCritical", "must not be more than walletWarning")
+		}
+
+		if chain.StartBlock != "" {
+			if _, err := client.ParseStartBlock(string(chain.StartBlock)); err != nil {
+				field("startBlock", "%s", err)
+			}
+		}
+
+		if policy := chain.Rpc; policy != nil {
+			if policy.RateLimit < 0 || policy.Burst < 0 {
+				field("rpc", "rateLimit and burst must not be negative")
+			}
+			if policy.Retries != nil && *policy.Retries < 0 {
+				field("rpc.retries", "must not be negative")
+			}
+			if policy.RetryDelay < 0 || policy.MaxRetryDelay < 0 {
+				field("rpc", "retryDelay and maxRetryDelay must not be negative")
+			}
+		}
+
+		if signer := chain.Signer; !signer.IsKeystore() {
+			switch signer.Type {
+			case client.ClefSignerType, client.RemoteSignerType:
+				if !isHttpUrl(signer.Url) {
+					field("signer.url", "expected an http or https url, not %q", signer.Url)
+				}
+			default:
+				field("signer.type", "unknown signer %q: expected keystore, clef or remote", signer.Type)
+			}
+		}
+	}
+
+	for i, pair := range c.Pairs {
+		name := fmt.Sprintf("pairs[%d]", i)
+		if _, _, err := pairContracts(pair); err != nil {
+			add("", name+".topology", "%s", err)
+		}
+		for _, chain := range []string{pair.From, pair.To} {
+			if _, ok := c.Chain[chain]; !ok {
+				add("", name, "chain %q is not in networks", chain)
+			}
+		}
+		if pair.From == pair.To {
+			add("", name, "from and to are the same chain")
+		}
+	}
+
+	if c.Alerts != nil && c.Alerts.Webhook != "" && !isHttpUrl(c.Alerts.Webhook) {
+		add("", "alerts.webhook", "expected an http or https url, not %q", c.Alerts.Webhook)
+	}
+	if c.Api != nil && c.Api.Addr != "" && c.Api.Token == "" {
+		add("", "api.token", "missing; the api is not served without one")
+	}
+	return errs
+}
+
+// an address must be 20 bytes of hex, and if it has upper case letters, have a valid eip-55 checksum
+func validAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("missing")
+	}
+	if !common.IsHexAddress(address) || !strings.HasPrefix(address, "0x") {
+		return fmt.Errorf("invalid address %q: expected 0x and 40 hex digits", address)
+	}
+	digits := address[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if checksummed := common.HexToAddress(address).Hex(); checksummed != address {
+			return fmt.Errorf("invalid checksum of %s: expected %s", address, checksummed)
+		}
+	}
+	return nil
+}
+
+// the url of a node: http, https, ws, wss or the path of an ipc file
+func validNodeUrl(raw string) error {
+	if strings.HasSuffix(raw, ".ipc") && !strings.Contains(raw, "://") {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %s", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf("invalid url %q: expected http, https, ws, wss or the path of an ipc file", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: no host", raw)
+	}
+	return nil
+}
+
+func isHttpUrl(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// problems of the chains in names, and of the config outside networks; the problems
+// of other chains do not matter if they are not loaded
+func (errs configErrors) of(names []string) configErrors {
+	filtered := configErrors{}
+	for _, err := range errs {
+		if err.chain == "" || contains(names, err.chain) {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
+
+// configCommand checks a config without starting the relayer
+func configCommand(args []string) {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: ChainBridge config validate [--config ./config.json]")
+		os.Exit(2)
+	}
+	command := flag.NewFlagSet("config validate", flag.ExitOnError)
+	configPath := command.String("config", "./config.json", "path to the config file")
+	command.Parse(args[1:])
+
+	_, errs, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", *configPath, err)
+		os.Exit(1)
+	}
+	if len(errs) != 0 {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", *configPath, errs)
+		os.Exit(1)
+	}
+	fmt.Printf("%s is valid\n", *configPath)
+}
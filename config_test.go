@@ -0,0 +1,212 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	ioutil.WriteFile(path, []byte(`{
+		"networks": {
+			"kovan": {
+				"id": 42,
+				"url": "https://kovan.infura.io",
+				"contractAddr": "0x42ad30c467746e5790cc8944f9c6b4098cab85a5",
+				"gasPrice": 100000000,
+				"from": "0x83a8e0bd54ff6dc11da80151563b8150534280be"
+			},
+			"ropsten": {
+				"id": 42,
+				"url": "ropsten.infura.io",
+				"contractAddr": "0x51f4A0f0D3bf30600d07396dAde1eE2e4Bca9b5e",
+				"from": "0x00",
+				"signer": {"type": "clef"}
+			}
+		},
+		"pairs": [{"topology": "lock-mint", "from": "kovan", "to": "rsk"}]
+	}`), 0644)
+
+	_, errs, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"networks.ropsten.id: 42 is also the id of kovan",
+		`networks.ropsten.url: invalid url "ropsten.infura.io"`,
+		"networks.ropsten.contractAddr: invalid checksum",
+		`networks.ropsten.from: invalid address "0x00"`,
+		"networks.ropsten.gasPrice: missing",
+		"networks.ropsten.signer.url: expected an http or https url",
+		`pairs[0]: chain "rsk" is not in networks`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d problems, got %s", len(expected), errs)
+	}
+	for i, e := range expected {
+		if !strings.HasPrefix(errs[i].Error(), e) {
+			t.Errorf("got %q expected %q", errs[i], e)
+		}
+	}
+
+	// only the problems of the chains that are loaded matter
+	if problems := errs.of([]string{"kovan"}); len(problems) != 1 {
+		t.Fatalf("expected only the problem with pairs, got %s", problems)
+	}
+
+	ioutil.WriteFile(path, []byte(`{"networks": {"kovan": {"gasprice": 1}}}`), 0644)
+	if _, errs, _ := loadConfig(path); !strings.Contains(errs.Error(), "gasprice: unknown field; did you mean gasPrice?") {
+		t.Fatalf("expected an unknown field error: %s", errs)
+	}
+}
+
+func TestValidateConfigReportsEveryProblem(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	ioutil.WriteFile(path, []byte(`{
+		"networks": {
+			"kovan": {
+				"id": 42,
+				"url": "https://kovan.infura.io",
+				"contractAddr": "0x42ad30c467746e5790cc8944f9c6b4098cab85a5",
+				"gasPrice": "lots",
+				"from": "0x83a8e0bd54ff6dc11da80151563b8150534280be",
+				"gasprice": 1,
+				"minBalance": "100000000000000000000"
+			}
+		},
+		"alert": {"webhook": "https://hooks.example.com"}
+	}`), 0644)
+
+	_, errs, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"alert: unknown field",
+		"networks.kovan.gasPrice: ",
+		"networks.kovan.gasprice: unknown field",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d problems, got %s", len(expected), errs)
+	}
+	for i, e := range expected {
+		if !strings.HasPrefix(errs[i].Error(), e) {
+			t.Errorf("got %q expected %q", errs[i], e)
+		}
+	}
+}
+
+func TestValidAddress(t *testing.T) {
+	valid := []string{
+		"0x51F4A0f0D3bf30600d07396dAde1eE2e4Bca9b5e",
+		"0x51f4a0f0d3bf30600d07396dade1ee2e4bca9b5e",
+		"0x51F4A0F0D3BF30600D07396DADE1EE2E4BCA9B5E",
+	}
+	for _, address := range valid {
+		if err := validAddress(address); err != nil {
+			t.Errorf("%s: %s", address, err)
+		}
+	}
+	invalid := []string{"", "0x00", "51f4a0f0d3bf30600d07396dade1ee2e4bca9b5e", "0x51F4A0f0D3bf30600d07396dAde1eE2e4Bca9b5E"}
+	for _, address := range invalid {
+		if err := validAddress(address); err == nil {
+			t.Errorf("%s is not a valid address", address)
+		}
+	}
+}
+
+func TestLoadConfigDir(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "networks"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "base.yaml"), []byte(`
+pairs:
+  - topology: lock-mint
+    from: kovan
+    to: ropsten
+alerts:
+  webhook: https://hooks.example.com/${TEST_WEBHOOK_KEY}
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "networks", "kovan.toml"), []byte(`
+id = 42
+url = "https://kovan.infura.io"
+contractAddr = "0x42ad30c467746e5790cc8944f9c6b4098cab85a5"
+gasPrice = 1000000000
+from = "0x83a8e0bd54ff6dc11da80151563b8150534280be"
+minBalance = "100000000000000000000"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "networks", "ropsten.json"), []byte(`{
+		"id": 3,
+		"url": "https://ropsten.infura.io",
+		"contractAddr": "0x51F4A0f0D3bf30600d07396dAde1eE2e4Bca9b5e",
+		"gasPrice": 1000000000,
+		"from": "0x83a8e0bd54ff6dc11da80151563b8150534280be"
+	}`), 0644)
+
+	os.Setenv("TEST_WEBHOOK_KEY", "secret")
+	os.Setenv("CHAINBRIDGE_NETWORKS_KOVAN_URL", "http://127.0.0.1:8545")
+	os.Setenv("CHAINBRIDGE_NETWORKS_ROPSTEN_RPC_RETRIES", "5")
+	defer os.Unsetenv("TEST_WEBHOOK_KEY")
+	defer os.Unsetenv("CHAINBRIDGE_NETWORKS_KOVAN_URL")
+	defer os.Unsetenv("CHAINBRIDGE_NETWORKS_ROPSTEN_RPC_RETRIES")
+
+	config, errs, err := loadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	kovan, ropsten := config.Chain["kovan"], config.Chain["ropsten"]
+	if kovan.Url != "http://127.0.0.1:8545" || kovan.Id.Big().Int64() != 42 || kovan.MinBalance.Big().String() != "100000000000000000000" {
+		t.Fatalf("kovan: %+v", kovan)
+	}
+	if ropsten.Rpc == nil || *ropsten.Rpc.Retries != 5 {
+		t.Fatalf("ropsten rpc: %+v", ropsten.Rpc)
+	}
+	if config.Alerts.Webhook != "https://hooks.example.com/secret" || config.Pairs[0].To != "ropsten" {
+		t.Fatalf("alerts %+v, pairs %+v", config.Alerts, config.Pairs[0])
+	}
+
+	// an override of a chain that is not in the config is an error, not ignored
+	os.Setenv("CHAINBRIDGE_NETWORKS_RINKEBY_URL", "http://127.0.0.1:8545")
+	defer os.Unsetenv("CHAINBRIDGE_NETWORKS_RINKEBY_URL")
+	if _, _, err := loadConfig(dir); err == nil || !strings.Contains(err.Error(), "RINKEBY") {
+		t.Fatalf("expected an error for rinkeby: %v", err)
+	}
+	os.Unsetenv("CHAINBRIDGE_NETWORKS_RINKEBY_URL")
+
+	os.Unsetenv("TEST_WEBHOOK_KEY")
+	if _, _, err := loadConfig(dir); err == nil || !strings.Contains(err.Error(), "TEST_WEBHOOK_KEY") {
+		t.Fatalf("expected an error for the unset variable: %v", err)
+	}
+}
+
+func TestLoadConfigBigNumber(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+
+	// yaml reads an unquoted amount of wei bigger than 64 bits as a float, which would round it
+	ioutil.WriteFile(path, []byte("networks:\n  kovan:\n    minBalance: 100000000000000000001\n"), 0644)
+	if _, err := readConfigFile(path); err == nil || !strings.Contains(err.Error(), "networks.kovan.minBalance") {
+		t.Fatalf("expected an error for the unquoted amount: %v", err)
+	}
+
+	ioutil.WriteFile(path, []byte("networks:\n  kovan:\n    minBalance: \"100000000000000000001\"\n"), 0644)
+	tree, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kovan := tree["networks"].(map[string]interface{})["kovan"].(map[string]interface{})
+	if kovan["minBalance"] != "100000000000000000001" {
+		t.Fatalf("got min balance %v", kovan["minBalance"])
+	}
+}
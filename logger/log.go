@@ -1,32 +1,235 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
+type Level int
+
+// levels in increasing order of importance; only messages at or above the configured level are written
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	EventLevel // bridge events found on chain
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	EventLevel: "event",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+	FatalLevel: "fatal",
+}
+
+// colors of each level in console output
+var levelColors = map[Level]string{
+	DebugLevel: "\x1b[90m",
+	InfoLevel:  "\x1b[92m",
+	EventLevel: "\x1b[94m",
+	WarnLevel:  "\x1b[93m",
+	ErrorLevel: "\x1b[91m",
+	FatalLevel: "\x1b[91m",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.ToLower(s) == name {
+			return level, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q: expected debug, info, event, warn or error", s)
+}
+
+// output formats
+const (
+	ConsoleFormat = "console" // colored lines, with fields appended as key=value
+	JsonFormat    = "json"    // one json object per line
+)
+
+// context of a message, eg. the chain, tx hash and block it is about
+type Fields map[string]interface{}
+
+type Config struct {
+	Level      Level
+	Format     string
+	File       string // write to this file instead of stdout
+	MaxSize    int64  // rotate the file once it is larger than this many megabytes; 0 never rotates
+	MaxBackups int    // number of rotated files to keep
+}
+
+// where and how messages are written; shared by every Logger
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format string
+	color  bool
+}
+
+var out = &output{w: os.Stdout, level: InfoLevel, format: ConsoleFormat, color: true}
+
+// Setup sets the level, format and destination of every message logged
+func Setup(config Config) error {
+	var w io.Writer = os.Stdout
+	if config.File != "" {
+		file, err := NewRotatingFile(config.File, config.MaxSize*1024*1024, config.MaxBackups)
+		if err != nil {
+			return err
+		}
+		w = file
+	}
+
+	format := config.Format
+	if format == "" {
+		format = ConsoleFormat
+	}
+	if format != ConsoleFormat && format != JsonFormat {
+		return fmt.Errorf("unknown log format %q: expected console or json", format)
+	}
+
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	out.w = w
+	out.level = config.Level
+	out.format = format
+	out.color = config.File == ""
+	return nil
+}
+
+// Logger logs messages with a set of context fields
+type Logger struct {
+	fields Fields
+}
+
+var root = &Logger{}
+
+// With returns a logger that adds fields to every message
+func With(fields Fields) *Logger {
+	return root.With(fields)
+}
+
+func (l *Logger) With(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{fields: merged}
+}
+
+func (l *Logger) log(level Level, format string, a ...interface{}) {
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if level < out.level {
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	if out.format == JsonFormat {
+		entry := make(map[string]interface{}, len(l.fields)+3)
+		for k, v := range l.fields {
+			entry[k] = v
+		}
+		entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+		entry["level"] = level.String()
+		entry["msg"] = msg
+		line, err := json.Marshal(entry)
+		if err != nil {
+			line = []byte(fmt.Sprintf(`{"level":"error","msg":"could not encode log entry: %s"}`, err))
+		}
+		out.w.Write(append(line, '\n'))
+		return
+	}
+
+	prefix := level.String() + ":"
+	if out.color {
+		prefix = levelColors[level] + prefix + "\x1b[0m"
+	} else {
+		prefix = time.Now().Format("2006-01-02 15:04:05") + " " + prefix
+	}
+	fmt.Fprintln(out.w, prefix, msg+l.fieldString())
+}
+
+// fields as " key=value ...", sorted by key
+func (l *Logger) fieldString() string {
+	if len(l.fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	s := ""
+	for _, k := range keys {
+		s += fmt.Sprintf(" %s=%v", k, l.fields[k])
+	}
+	return s
+}
+
+func (l *Logger) Debug(format string, a ...interface{}) {
+	l.log(DebugLevel, format, a...)
+}
+
+func (l *Logger) Info(format string, a ...interface{}) {
+	l.log(InfoLevel, format, a...)
+}
+
+func (l *Logger) Event(format string, a ...interface{}) {
+	l.log(EventLevel, format, a...)
+}
+
+func (l *Logger) Warn(format string, a ...interface{}) {
+	l.log(WarnLevel, format, a...)
+}
+
+func (l *Logger) Error(format string, a ...interface{}) {
+	l.log(ErrorLevel, format, a...)
+}
+
+func (l *Logger) FatalError(format string, a ...interface{}) {
+	l.log(FatalLevel, format, a...)
+	os.Exit(1)
+}
+
+func Debug(format string, a ...interface{}) {
+	root.Debug(format, a...)
+}
+
 func Info(format string, a ...interface{}) {
-	out := fmt.Sprintf(format, a...)
-	fmt.Println("\x1b[92minfo:\x1b[0m", out)
+	root.Info(format, a...)
 }
 
 func Warn(format string, a ...interface{}) {
-	out := fmt.Sprintf(format, a...)
-	fmt.Println("\x1b[93mwarn:\x1b[0m", out)
+	root.Warn(format, a...)
 }
 
 func Error(format string, a ...interface{}) {
-	out := fmt.Sprintf(format, a...)
-	fmt.Println("\x1b[91merror:\x1b[0m", out)
+	root.Error(format, a...)
 }
 
 func FatalError(format string, a ...interface{}) {
-	out := fmt.Sprintf(format, a...)
-	fmt.Println("\x1b[91merror:\x1b[0m", out)	
-	os.Exit(1)
+	root.FatalError(format, a...)
 }
 
 func Event(format string, a ...interface{}) {
-	out := fmt.Sprintf(format, a...)
-	fmt.Println("\x1b[94mevent:\x1b[0m", out)
-}
\ No newline at end of file
+	root.Event(format, a...)
+}
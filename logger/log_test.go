@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJsonOutput(t *testing.T) {
+	var buf bytes.Buffer
+	out.w, out.level, out.format = &buf, InfoLevel, JsonFormat
+	defer func() { out.w, out.level, out.format = os.Stdout, InfoLevel, ConsoleFormat }()
+
+	With(Fields{"chain": "kovan", "block": 7}).Event("deposit of %d wei", 10)
+	Debug("not logged below the level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d: %s", len(lines), buf.String())
+	}
+
+	entry := make(map[string]interface{})
+	err := json.Unmarshal([]byte(lines[0]), &entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"level": "event", "msg": "deposit of 10 wei", "chain": "kovan", "block": float64(7)}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("%s -- got: %v expected: %v", k, entry[k], v)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bridge.log")
+	r, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		_, err = r.Write([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{path: "dddddddd\n", path + ".1": "cccccccc\n", path + ".2": "bbbbbbbb\n"}
+	for file, content := range expected {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s -- got: %q expected: %q", file, data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups to be kept")
+	}
+}
@@ -1,22 +1,25 @@
 package main
 
 import (
-	"encoding/hex"
+	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/console"
 
 	"github.com/ChainSafe/ChainBridge/client"
 	"github.com/ChainSafe/ChainBridge/logger"
@@ -27,18 +30,104 @@ var flags map[string]bool
 var ks *keystore.KeyStore
 
 type Config struct {
-	Chain map[string]*Chain `json:"networks"`
+	Chain  map[string]*Chain `json:"networks"`
+	Pairs  []*Pair           `json:"pairs,omitempty"`
+	Alerts *Alerts           `json:"alerts,omitempty"`
+	Api    *Api              `json:"api,omitempty"`
+}
+
+// admin api to inspect and control the relayer; not served if there is no addr
+type Api struct {
+	Addr  string `json:"addr,omitempty"`  // eg. 127.0.0.1:8545
+	Token string `json:"token,omitempty"` // every request must send "Authorization: Bearer <token>"
+}
+
+// where to send alerts, eg. when a bridge contract is low on funds; alerts are always logged
+type Alerts struct {
+	Webhook string `json:"webhook,omitempty"` // url to post alerts to as json
+}
+
+// a pair of chains bridged with a particular topology. chains that are not in any pair
+// use the symmetric Bridge contract.
+// "foreign-home": users deposit to the Foreign contract on From, the relayer withdraws from the Home contract on To
+// "lock-mint": deposits lock ether in the Bridge contract on From and the relayer mints the Wrapped token on To;
+// burning the token on To releases the locked ether on From
+type Pair struct {
+	Topology string `json:"topology"`
+	From     string `json:"from"`
+	To       string `json:"to"`
 }
 
 type Chain struct {
-	Name       string   `json:"name"`
-	Url        string   `json:"url"`
-	Id         *big.Int `json:"id,omitempty"`
-	Contract   string   `json:"contractAddr"`
-	GasPrice   *big.Int `json:"gasPrice"`
-	From       string   `json:"from"`
-	Password   string   `json:"password,omitempty"`
-	StartBlock int      `json:"startBlock,omitempty"`
+	Name     string  `json:"name"`
+	Url      string  `json:"url"`
+	Id       *BigInt `json:"id,omitempty"`
+	Contract string  `json:"contractAddr"`
+	GasPrice *BigInt `json:"gasPrice"`
+	From     string  `json:"from"`
+	Password string  `json:"password,omitempty"`
+	// signs txs sent from the from account: the keystore by default, or clef or a remote signing service
+	Signer *client.SignerConfig `json:"signer,omitempty"`
+	// keystore directory holding the from account; defaults to --keystore
+	Keystore string `json:"keystore,omitempty"`
+	// file holding the password of the from account; defaults to --password-file
+	PasswordFile string `json:"passwordFile,omitempty"`
+	// environment variable holding the password of the from account, looked at before any other source
+	PasswordEnv string    `json:"passwordEnv,omitempty"`
+	StartBlock  BlockSpec `json:"startBlock,omitempty"`
+	// maximum number of blocks per eth_getLogs query; hosted providers often limit this
+	MaxBlockRange uint64 `json:"maxBlockRange,omitempty"`
+	// rpc endpoints to fail over between, used instead of url
+	Urls []*client.Endpoint `json:"urls,omitempty"`
+	// avoid endpoints more than this many blocks behind the others; defaults to 5
+	MaxLag uint64 `json:"maxLag,omitempty"`
+	// rate limit of each endpoint and retries of rpc requests that fail with transient errors
+	Rpc *client.RpcPolicy `json:"rpc,omitempty"`
+	// alert when the bridge contract holds less than this many wei
+	MinBalance *BigInt `json:"minBalance,omitempty"`
+	// warning and critical when the relayer account can pay gas for fewer than this many withdraws
+	WalletWarning  uint64 `json:"walletWarning,omitempty"`
+	WalletCritical uint64 `json:"walletCritical,omitempty"`
+}
+
+// startBlock in config.json can be given as a number or as a string; see client.ParseStartBlock
+type BlockSpec string
+
+func (b *BlockSpec) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = BlockSpec(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("startBlock must be a block number, \"latest\", \"latest-N\" or \"deployment\": %s", data)
+	}
+	*b = BlockSpec(n.String())
+	return nil
+}
+
+// a number in the config, eg. an amount of wei, given as a number or a decimal string,
+// since yaml and toml cannot hold integers of more than 64 bits
+type BigInt struct {
+	big.Int
+}
+
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if _, ok := b.SetString(s, 10); !ok {
+		return fmt.Errorf("expected an integer, not %s", data)
+	}
+	return nil
+}
+
+// the value of b, or nil if it is not set
+func (b *BigInt) Big() *big.Int {
+	if b == nil {
+		return nil
+	}
+	return &b.Int
 }
 
 // NewKeyStore creates a general keystore at given path
@@ -46,26 +135,33 @@ func newKeyStore(path string) *keystore.KeyStore {
 	return keystore.NewKeyStore(path, keystore.StandardScryptN, keystore.StandardScryptP)
 }
 
-// ReadAbi parses solidity/Bridge/build/Bridge.abi for events and stores the keccak hash of each in an Event struct
-func readAbi(verbose bool) *client.Events {
-	e := new(client.Events)
-
-	// read bridge contract abi
-	path, _ := filepath.Abs("./solidity/Bridge/build/contracts_Bridge_sol_Bridge.abi")
+// parse the abi of a contract at path
+func readContractAbi(path string) abi.ABI {
+	path, _ = filepath.Abs(path)
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		logger.Warn("Failed to read file: %s: will try to read solidity/build/Bridge.abi", err)
-		path, _ = filepath.Abs("./solidity/Bridge/build/Bridge.abi")
-		file, err = ioutil.ReadFile(path)
-		if err != nil {
-			logger.FatalError("Failed to read file: %s", err)
-		}
+		logger.FatalError("Failed to read file: %s", err)
 	}
 
-	bridgeabi, err := abi.JSON(strings.NewReader(string(file)))
+	contractAbi, err := abi.JSON(strings.NewReader(string(file)))
 	if err != nil {
 		logger.FatalError("Invalid abi: %s", err)
 	}
+	return contractAbi
+}
+
+// ReadAbi parses the abis in solidity/Bridge, solidity/Home, solidity/Foreign and solidity/Wrapped for events and
+// stores the keccak hash of each in an Event struct
+func readAbi(verbose bool) *client.Events {
+	e := new(client.Events)
+
+	// read bridge contract abi
+	bridgePath := "./solidity/Bridge/build/contracts_Bridge_sol_Bridge.abi"
+	if ok, _ := exists(bridgePath); !ok {
+		logger.Warn("Failed to read file: %s: will try to read solidity/build/Bridge.abi", bridgePath)
+		bridgePath = "./solidity/Bridge/build/Bridge.abi"
+	}
+	bridgeabi := readContractAbi(bridgePath)
 
 	// checking abi for events
 	bridgeEvents := bridgeabi.Events
@@ -76,11 +172,85 @@ func readAbi(verbose bool) *client.Events {
 	e.WithdrawId = bridgeEvents["Withdraw"].Id().Hex()
 	e.BridgeFundedId = bridgeEvents["BridgeFunded"].Id().Hex()
 	e.PaidId = bridgeEvents["Paid"].Id().Hex()
-	// e.AuthorityAddedId = bridgeEvents["AuthorityAdded"].Id().Hex()
+	e.SignedForWithdrawId = bridgeEvents["SignedForWithdraw"].Id().Hex()
+	e.AuthorityAddedId = bridgeEvents["AuthorityAdded"].Id().Hex()
+	e.AuthorityRemovedId = bridgeEvents["AuthorityRemoved"].Id().Hex()
+	e.ThresholdUpdatedId = bridgeEvents["ThresholdUpdated"].Id().Hex()
+	e.BridgeSetId = bridgeEvents["BridgeSet"].Id().Hex()
+
+	// Home and Foreign share Deposit, Withdraw, ContractCreation and BridgeFunded with Bridge;
+	// the owner withdraw event of Foreign has its own signature
+	homeEvents := readContractAbi("./solidity/Home/build/Home.abi").Events
+	if homeEvents["Withdraw"].Id().Hex() != e.WithdrawId {
+		logger.FatalError("Withdraw event of Home does not match Bridge")
+	}
+	foreignEvents := readContractAbi("./solidity/Foreign/build/Foreign.abi").Events
+	if foreignEvents["Deposit"].Id().Hex() != e.DepositId {
+		logger.FatalError("Deposit event of Foreign does not match Bridge")
+	}
+	e.ForeignWithdrawId = foreignEvents["Withdraw"].Id().Hex()
+
+	wrappedEvents := readContractAbi("./solidity/Wrapped/build/Wrapped.abi").Events
+	e.MintId = wrappedEvents["Mint"].Id().Hex()
+	e.BurnId = wrappedEvents["Burn"].Id().Hex()
 
 	return e
 }
 
+// contract types of the chains in each pair
+func pairContracts(pair *Pair) (string, string, error) {
+	switch pair.Topology {
+	case "", client.TopologyBridge:
+		return client.BridgeContract, client.BridgeContract, nil
+	case client.TopologyForeignHome:
+		return client.ForeignContract, client.HomeContract, nil
+	case client.TopologyLockMint:
+		return client.BridgeContract, client.WrappedContract, nil
+	}
+	return "", "", fmt.Errorf("unknown topology %q", pair.Topology)
+}
+
+// set the contract type of every chain from the pairs in the config, and the origin chain of every wrapped token and home contract
+func applyPairs(pairs []*Pair, clients []*client.Chain) {
+	for _, chain := range clients {
+		chain.ContractType = client.BridgeContract
+	}
+
+	assigned := make(map[string]string)
+	origins := make(map[string]string)
+	for _, pair := range pairs {
+		fromType, toType, err := pairContracts(pair)
+		if err != nil {
+			logger.FatalError("pair %s => %s: %s", pair.From, pair.To, err)
+		}
+
+		for name, contractType := range map[string]string{pair.From: fromType, pair.To: toType} {
+			if prev, ok := assigned[name]; ok && prev != contractType {
+				logger.FatalError("chain %s cannot have both a %s and a %s contract", name, prev, contractType)
+			}
+			assigned[name] = contractType
+
+			chain := client.FindChainByName(name, clients)
+			if chain != nil {
+				chain.ContractType = contractType
+			}
+		}
+
+		// a wrapped token is backed by, and a home contract pays out for, only the chain it is paired with
+		if pair.Topology == client.TopologyLockMint || pair.Topology == client.TopologyForeignHome {
+			if prev, ok := origins[pair.To]; ok && prev != pair.From {
+				logger.FatalError("chain %s cannot be paired with both %s and %s", pair.To, prev, pair.From)
+			}
+			origins[pair.To] = pair.From
+
+			to := client.FindChainByName(pair.To, clients)
+			if to != nil {
+				to.Origin = client.FindChainByName(pair.From, clients)
+			}
+		}
+	}
+}
+
 // check if file or directory at path exists
 func exists(path string) (bool, error) {
 	_, err := os.Stat(path)
@@ -94,7 +264,8 @@ func exists(path string) (bool, error) {
 }
 
 // create log/ directory if it does not exist
-// will store the latest block number read
+// will store the latest block number read; returns the block to start scanning from,
+// or nil if no block has been saved for the chain
 func startup(id *big.Int) *big.Int {
 	logExists, err := exists("log")
 	if err != nil {
@@ -102,20 +273,79 @@ func startup(id *big.Int) *big.Int {
 	}
 
 	if !logExists {
-		logger.Info("creating log/ directory...")	
+		logger.Info("creating log/ directory...")
 		err = os.Mkdir("./log", os.ModePerm)
 		if err != nil {
 			logger.Error(err.Error())
 		}
-	} 
+	}
 
 	path, _ := filepath.Abs("./log/" + id.String() + "_lastblock.txt")
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		logger.Warn("%s", err)
+		if !os.IsNotExist(err) {
+			logger.Warn("%s", err)
+		}
+		return nil
+	}
+
+	// the file holds the last block that was fully scanned
+	startBlock, ok := new(big.Int).SetString(strings.TrimSpace(string(file)), 10)
+	if !ok {
+		logger.Warn("invalid last block in %s", path)
+		return nil
+	}
+	return startBlock.Add(startBlock, big.NewInt(1))
+}
+
+// parse the --start-block flag, which is either a single start block for every chain
+// or a comma separated list of chain:block, eg. "kovan:latest,ropsten:5000000"
+func parseStartBlockFlag(value string) map[string]string {
+	overrides := make(map[string]string)
+	if value == "" {
+		return overrides
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) == 2 {
+			overrides[strings.TrimSpace(kv[0])] = kv[1]
+		} else {
+			overrides["*"] = kv[0]
+		}
 	}
-	startBlock := new(big.Int)
-	startBlock.SetString(string(file), 10)
+	return overrides
+}
+
+// work out the block to start listening from on chain. in order of precedence:
+// 1. --start-block
+// 2. the last block saved in log/<id>_lastblock.txt
+// 3. startBlock in config.json
+// 4. the block the bridge contract was deployed in
+func resolveStartBlock(chain *client.Chain, events *client.Events, override string, configured BlockSpec) *big.Int {
+	source := "--start-block"
+	spec := override
+	if spec == "" && chain.StartBlock != nil {
+		logger.Info("resuming %s from saved block %s", chain.Name, chain.StartBlock)
+		return chain.StartBlock
+	}
+	if spec == "" {
+		source, spec = "config", string(configured)
+	}
+	if spec == "" {
+		source, spec = "default", client.StartDeployment
+	}
+
+	parsed, err := client.ParseStartBlock(spec)
+	if err != nil {
+		logger.FatalError("%s start block of %s: %s", source, chain.Name, err)
+	}
+	startBlock, err := client.ResolveStartBlock(chain, events, parsed)
+	if err != nil {
+		logger.FatalError("could not resolve start block %q of %s: %s", spec, chain.Name, err)
+	}
+
+	logger.Info("starting %s from block %s (%s start block %q)", chain.Name, startBlock, source, spec)
 	return startBlock
 }
 
@@ -133,17 +363,37 @@ func main() {
 	headerPtr := flag.Bool("header", true, "a bool representing whether to print out the header or not")
 	verbosePtr := flag.Bool("v", false, "increase verbosity of output")
 	readAllPtr := flag.Bool("a", false, "a bool representing whether to read logs from every contract or not")
-	configPtr := flag.String("config", "./config.json", "a string of the path to the config file")
+	configPtr := flag.String("config", "./config.json", "a string of the path to the config file: json, yaml or toml, or a directory of them")
 	keysPtr := flag.String("keystore", "./keystore", "a string of the path to the keystore directory")
-	// password flag assumes you have the same account on every chain
-	passwordPtr := flag.String("password", "password", "a string of the password to the account specified in the config file")
+	// --password is visible to other users in the process list; prefer --password-file or CHAINBRIDGE_PASSWORD.
+	// chains with their own accounts can set keystore, passwordFile and passwordEnv in the config
+	passwordPtr := flag.String("password", "", "password of every account in the keystore; prefer --password-file or the CHAINBRIDGE_PASSWORD environment variable")
+	passwordFilePtr := flag.String("password-file", "", "file only readable by its owner holding the password of every account, or lines of 0x<address>=<password>")
 	noListenPtr := flag.Bool("no-listen", false, "a bool; if true, do not start the listener")
+	logLevelPtr := flag.String("log-level", "info", "minimum level of messages to log: debug, info, event, warn or error; -v lowers it to debug")
+	logFormatPtr := flag.String("log-format", "console", "format of log output: console or json")
+	logFilePtr := flag.String("log-file", "", "file to write logs to instead of stdout")
+	logMaxSizePtr := flag.Int64("log-max-size", 100, "size in megabytes at which the log file is rotated; 0 never rotates")
+	logMaxBackupsPtr := flag.Int("log-max-backups", 5, "number of rotated log files to keep")
+	metricsPtr := flag.String("metrics", "", "address to serve prometheus metrics on at /metrics, eg. :9100; metrics are not served if empty")
+	queueSizePtr := flag.Int("queue-size", 100, "number of deposits that can wait to be relayed to each chain before listeners stop to wait for them")
+	shutdownTimeoutPtr := flag.Duration("shutdown-timeout", 30*time.Second, "how long to wait on shutdown for withdraws that have been sent to be mined")
+	startBlockPtr := flag.String("start-block", "", "block to start listening from, overriding the saved last block: a number, latest, latest-N or deployment; or a list of chain:block")
 
 	/* subcommands */
 	depositCommand := flag.NewFlagSet("deposit", flag.ExitOnError)
 	fundCommand := flag.NewFlagSet("fund", flag.ExitOnError)
 	payCommand := flag.NewFlagSet("payCommand", flag.ExitOnError)
 	withdrawCommand := flag.NewFlagSet("withrawCommand", flag.ExitOnError)
+	burnCommand := flag.NewFlagSet("burn", flag.ExitOnError)
+	statusCommand := flag.NewFlagSet("status", flag.ExitOnError)
+	replayCommand := flag.NewFlagSet("replay", flag.ExitOnError)
+	replayChainPtr := replayCommand.String("chain", "", "name of the chain to replay")
+	replayFromPtr := replayCommand.Int64("from", 0, "block to start replaying from")
+	replayToPtr := replayCommand.Int64("to", -1, "block to replay up to; defaults to the latest block")
+	replayChunkPtr := replayCommand.Uint64("chunk", 0, "maximum number of blocks to query at a time; defaults to the chain's maxBlockRange")
+	replayRelayPtr := replayCommand.Bool("relay", false, "re-relay deposits that have no withdraw on their destination chain")
+	replayDryRunPtr := replayCommand.Bool("dry-run", false, "only print the withdrawals that would be relayed")
 
 	/* admin subcommands */
 	addAuthority := flag.NewFlagSet("addauth", flag.ExitOnError)
@@ -160,18 +410,50 @@ func main() {
 			payCommand.Parse(os.Args[2:])
 		case "withdraw":
 			withdrawCommand.Parse(os.Args[2:])
+		case "burn":
+			burnCommand.Parse(os.Args[2:])
+		case "status":
+			statusCommand.Parse(os.Args[2:])
+		case "replay":
+			replayCommand.Parse(os.Args[2:])
 		case "addauth":
 			addAuthority.Parse(os.Args[2:])
 		case "removeauth":
 			removeAuthory.Parse(os.Args[2:])
+		case "account":
+			accountCommand(os.Args[2:])
+			return
+		case "config":
+			configCommand(os.Args[2:])
+			return
 		default:
 			// continue
 		}
 	}
 
 	flag.Parse()
+
+	logLevel, err := logger.ParseLevel(*logLevelPtr)
+	if err != nil {
+		logger.FatalError("%s", err)
+	}
+	if *verbosePtr && logLevel > logger.DebugLevel {
+		logLevel = logger.DebugLevel
+	}
+	err = logger.Setup(logger.Config{
+		Level:      logLevel,
+		Format:     *logFormatPtr,
+		File:       *logFilePtr,
+		MaxSize:    *logMaxSizePtr,
+		MaxBackups: *logMaxBackupsPtr,
+	})
+	if err != nil {
+		logger.FatalError("could not set up logging: %s", err)
+	}
+
+	// the banner is not a log entry, so leave it out of json logs
 	header := *headerPtr
-	if header {
+	if header && *logFormatPtr != logger.JsonFormat {
 		printHeader()
 	}
 
@@ -194,17 +476,19 @@ func main() {
 	password := *passwordPtr
 	noListen := *noListenPtr
 
-	var isSubCommandParsed [4]bool
+	var isSubCommandParsed [5]bool
 	isSubCommandParsed[0] = depositCommand.Parsed()
 	isSubCommandParsed[1] = fundCommand.Parsed()
 	isSubCommandParsed[2] = payCommand.Parsed()
 	isSubCommandParsed[3] = withdrawCommand.Parsed()
+	isSubCommandParsed[4] = burnCommand.Parsed()
 
-	var subCommandArgs [4][]string
+	var subCommandArgs [5][]string
 	subCommandArgs[0] = depositCommand.Args()
 	subCommandArgs[1] = fundCommand.Args()
 	subCommandArgs[2] = payCommand.Args()
 	subCommandArgs[3] = withdrawCommand.Args()
+	subCommandArgs[4] = burnCommand.Args()
 
 	var chains []string
 
@@ -215,16 +499,16 @@ func main() {
 	for commandIndex, subCommand := range isSubCommandParsed {
 		if subCommand {
 			for paramIndex, param := range subCommandArgs[commandIndex] {
-				if strings.Contains(param, "--password") {
+				if param == "--password" || strings.HasPrefix(param, "--password=") {
 					/*
-						Check if the index of the password flag == same length of all subcommand parameters
-						If == => --password="keystorePassword"
-						else if password flag index == length - 1 =>  --password keystorePassword
+						--password="keystorePassword" or --password keystorePassword
 					*/
-					if paramIndex == len(subCommandArgs[commandIndex])-1 {
-						password = subCommandArgs[commandIndex][paramIndex][11:len(subCommandArgs[commandIndex][paramIndex])]
-					} else {
+					if strings.HasPrefix(param, "--password=") {
+						password = param[11:]
+					} else if paramIndex < len(subCommandArgs[commandIndex])-1 {
 						password = subCommandArgs[commandIndex][paramIndex+1]
+					} else {
+						logger.FatalError("--password needs a value")
 					}
 					chains = subCommandArgs[commandIndex][0:paramIndex]
 					break
@@ -253,6 +537,19 @@ func main() {
 
 	/* keys */
 	ks = newKeyStore(keystorePath)
+	if password != "" {
+		logger.Warn("--password can be seen by other users of this machine; use --password-file or %s instead", client.PasswordEnv)
+	}
+	// chains can have their own keystore and password source, so each can have a distinct relayer account
+	keystores := map[string]*keystore.KeyStore{keystorePath: ks}
+	passwordSources := map[string]*client.PasswordSource{}
+	passwordSource := func(file string, env string) *client.PasswordSource {
+		key := file + "\x00" + env
+		if _, ok := passwordSources[key]; !ok {
+			passwordSources[key] = &client.PasswordSource{File: file, Env: env, Flag: password, Prompt: true}
+		}
+		return passwordSources[key]
+	}
 	ksaccounts := ks.Accounts()
 	for i, account := range ksaccounts {
 		if verbose {
@@ -262,19 +559,89 @@ func main() {
 
 	// config file reading
 	path, _ := filepath.Abs(configStr)
-	file, err := ioutil.ReadFile(path)
+	config, configProblems, err := loadConfig(path)
 	if err != nil {
-		logger.FatalError("Failed to read file: %s", err)
+		logger.FatalError("could not read config: %s", err)
 	}
 
-	clients := make([]*client.Chain, len(chains))
+	// chains loaded only in case the status or replay commands need them
+	optional := map[string]bool{}
 
-	// unmarshal config
-	config := new(Config)
-	err = json.Unmarshal(file, config)
-	if err != nil {
-		logger.FatalError("could not unmarshal config: %s", err)
+	// the status command needs every chain in the config, since the deposit could be to any of them
+	statusArgs := statusCommand.Args()
+	if statusCommand.Parsed() {
+		if len(statusArgs) != 2 {
+			logger.FatalError("usage: ChainBridge status <chain> <txHash>")
+		}
+		chains = []string{statusArgs[0]}
+		for name := range config.Chain {
+			if name != statusArgs[0] {
+				chains = append(chains, name)
+				optional[name] = true
+			}
+		}
+	}
+
+	// the replay command may relay deposits to any chain in the config
+	if replayCommand.Parsed() {
+		if *replayChainPtr == "" {
+			logger.FatalError("usage: ChainBridge replay --chain <chain> [--from N] [--to M] [--relay] [--dry-run]")
+		}
+		chains = []string{*replayChainPtr}
+		for name := range config.Chain {
+			if name != *replayChainPtr {
+				chains = append(chains, name)
+				optional[name] = true
+			}
+		}
+	}
+
+	// burning releases ether on the origin chain of the wrapped token, so load it as well
+	burnChains := chains
+	if burnCommand.Parsed() {
+		for _, pair := range config.Pairs {
+			if pair.Topology != client.TopologyLockMint {
+				continue
+			}
+			for _, name := range burnChains {
+				if name == pair.To && !contains(chains, pair.From) {
+					chains = append(chains, pair.From)
+				}
+			}
+		}
+	}
+
+	// every problem with the chains that are loaded is reported at once; optional chains with problems are left out
+	fatal := configErrors{}
+	skipped := map[string]bool{}
+	for _, problem := range configProblems.of(chains) {
+		if optional[problem.chain] {
+			logger.Warn("not loading chain %s: %s", problem.chain, problem)
+			skipped[problem.chain] = true
+		} else {
+			fatal = append(fatal, problem)
+		}
+	}
+	if len(fatal) != 0 {
+		logger.FatalError("%s: %s\nrun `ChainBridge config validate` to check the whole config", configStr, fatal)
+	}
+	loaded := []string{}
+	for _, name := range chains {
+		if !skipped[name] {
+			loaded = append(loaded, name)
+		}
 	}
+	chains = loaded
+
+	// only the listener and the commands that send txs sign with the relayer accounts, so only they unlock them
+	sends := replayCommand.Parsed() && *replayRelayPtr && !*replayDryRunPtr
+	for _, parsed := range isSubCommandParsed {
+		sends = sends || parsed
+	}
+	listens := !noListen && commandsNotParsed == len(isSubCommandParsed) && !statusCommand.Parsed() && !replayCommand.Parsed() && !addAuthority.Parsed()
+	unlock := sends || listens
+
+	clients := make([]*client.Chain, len(chains))
 
 	// read config file for each chain id
 	for i, name := range chains {
@@ -285,61 +652,118 @@ func main() {
 		}
 
 		clients[i] = new(client.Chain)
-		clients[i].Id = config.Chain[name].Id
+		clients[i].Id = config.Chain[name].Id.Big()
 		clients[i].Name = name
 
-		// to start at block 0, `rm -rf log/`
+		// to start over, `rm -rf log/` or use --start-block; to reprocess a range of blocks, use `ChainBridge replay`
 		startBlock := startup(clients[i].Id)
 		clients[i].StartBlock = startBlock
 
 		contractAddr := config.Chain[name].Contract
 		logger.Info("contract address of chain %s: %s", name, contractAddr)
-		contract := new(common.Address)
-		contractBytes, err := hex.DecodeString(contractAddr[2:])
-		if err != nil {
-			logger.FatalError("%s", err)
-		}
-		contract.SetBytes(contractBytes)
-		clients[i].Contract = contract
+		contract := common.HexToAddress(contractAddr)
+		clients[i].Contract = &contract
 
 		url := config.Chain[name].Url
+		preferred := 0
+		if len(config.Chain[name].Urls) != 0 {
+			url = ""
+		}
+		clients[i].Endpoints = config.Chain[name].Urls
+		clients[i].MaxLag = config.Chain[name].MaxLag
+		clients[i].RpcPolicy = config.Chain[name].Rpc
+		// urls takes the place of url, which is then the preferred endpoint
+		for _, endpoint := range clients[i].Endpoints {
+			logger.Info("endpoint of chain %s: %s, priority %d", name, endpoint.Url, endpoint.Priority)
+			if url == "" || endpoint.Priority < preferred {
+				url, preferred = endpoint.Url, endpoint.Priority
+			}
+		}
+		if url == "" {
+			logger.FatalError("chain %s has no url", name)
+		}
 		logger.Info("url of chain %s: %s", name, url)
 		clients[i].Url = url
 
-		gasPrice := config.Chain[name].GasPrice
+		gasPrice := config.Chain[name].GasPrice.Big()
 		clients[i].GasPrice = gasPrice
 
+		clients[i].MaxBlockRange = config.Chain[name].MaxBlockRange
+		clients[i].MinBalance = config.Chain[name].MinBalance.Big()
+		clients[i].WalletWarning = config.Chain[name].WalletWarning
+		clients[i].WalletCritical = config.Chain[name].WalletCritical
+
 		fromAccount := config.Chain[name].From
 		logger.Info("account to send txs from on chain %s: %s", name, fromAccount)
 		from := new(common.Address)
-		fromBytes, err := hex.DecodeString(fromAccount[2:])
+		*from = common.HexToAddress(fromAccount)
+		clients[i].From = from
+
+		if config.Chain[name].Password != "" {
+			logger.Warn("the password of chain %s in the config is ignored; use --password-file or %s instead", name, client.PasswordEnv)
+		}
+
+		chainKeystorePath := keystorePath
+		if config.Chain[name].Keystore != "" {
+			chainKeystorePath = config.Chain[name].Keystore
+			logger.Info("keystore of chain %s: %s", name, chainKeystorePath)
+		}
+		chainKeystore, ok := keystores[chainKeystorePath]
+		if !ok {
+			chainKeystore = newKeyStore(chainKeystorePath)
+			keystores[chainKeystorePath] = chainKeystore
+		}
+
+		signer, err := client.NewSigner(config.Chain[name].Signer, chainKeystore, *from)
 		if err != nil {
-			logger.FatalError("%s", err)
+			logger.FatalError("could not set up signer of chain %s: %s", name, err)
 		}
-		from.SetBytes(fromBytes)
-		clients[i].From = from
+		if unlock && config.Chain[name].Signer.IsKeystore() {
+			/* unlock account */
+			passwordFile := *passwordFilePtr
+			if config.Chain[name].PasswordFile != "" {
+				passwordFile = config.Chain[name].PasswordFile
+			}
+			accountPassword, err := passwordSource(passwordFile, config.Chain[name].PasswordEnv).Password(*from)
+			if err == nil {
+				err = client.UnlockAccount(chainKeystore, chainKeystorePath, *from, accountPassword)
+			}
+			if err != nil && optional[name] {
+				logger.Warn("not loading chain %s: could not unlock its account: %s", name, err)
+				skipped[name] = true
+				continue
+			}
+			if err != nil {
+				logger.FatalError("could not unlock account of chain %s: %s", name, err)
+			}
+		} else if !config.Chain[name].Signer.IsKeystore() {
+			logger.Info("signer of chain %s: %s", name, config.Chain[name].Signer.Type)
+		}
+		clients[i].Signer = signer
+	}
+
+	// leave out the optional chains whose accounts could not be unlocked
+	loadedClients := []*client.Chain{}
+	for i, name := range chains {
+		if !skipped[name] {
+			loadedClients = append(loadedClients, clients[i])
+		}
+	}
+	clients = loadedClients
 
-		clients[i].Password = password
+	applyPairs(config.Pairs, clients)
 
-		/* unlock account */
-		// if(ks.HasAddress(*from)) {
-		// 	account := new(accounts.Account)
-		// 	account.Address = *from
-		// 	err = ks.Unlock(*account, password)
-		// 	if err != nil {
-		// 		fmt.Println("could not unlock account")
-		// 		fmt.Println(err)
-		// 	} else {
-		// 		log.Fatal("account not found in keystore")
-		// 	}
-		// }
+	if config.Alerts != nil && config.Alerts.Webhook != "" {
+		client.SetNotifier(client.MultiNotifier{client.LogNotifier{}, client.NewWebhookNotifier(config.Alerts.Webhook)})
 	}
 
+	// every command needs the nodes, and the listener checks the contracts and start blocks before listening,
+	// so not being able to connect at startup is fatal; once listening, the listener stops if its node goes away
 	for _, chain := range clients {
 		/* dial client */
-		chainClient, err := ethclient.Dial(chain.Url)
+		chainClient, err := client.Dial(chain)
 		if err != nil {
-			log.Fatal(err)
+			logger.FatalError("could not connect to %s at %s: %s", chain.Name, chain.Url, err)
 		}
 		chain.Client = chainClient
 	}
@@ -383,6 +807,45 @@ func main() {
 			client.WithdrawToPrompt(chain, ks)
 		}
 		return
+	} else if burnCommand.Parsed() {
+		for _, name := range burnChains {
+			chain := client.FindChainByName(name, clients)
+			if chain == nil {
+				logger.FatalError("chain not found in config")
+			}
+			client.BurnPrompt(chain, ks)
+		}
+		return
+	} else if statusCommand.Parsed() {
+		chain := client.FindChainByName(statusArgs[0], clients)
+		if chain == nil {
+			logger.FatalError("chain not found in config")
+		}
+		status, err := client.GetTransferStatus(chain, clients, events, common.HexToHash(statusArgs[1]))
+		if err != nil {
+			logger.FatalError("could not get status of transfer: %s", err)
+		}
+		client.PrintStatus(status)
+		return
+	} else if replayCommand.Parsed() {
+		chain := client.FindChainByName(*replayChainPtr, clients)
+		if chain == nil {
+			logger.FatalError("chain not found in config")
+		}
+		opts := &client.ReplayOptions{
+			From:      big.NewInt(*replayFromPtr),
+			ChunkSize: *replayChunkPtr,
+			Relay:     *replayRelayPtr,
+			DryRun:    *replayDryRunPtr,
+		}
+		if *replayToPtr >= 0 {
+			opts.To = big.NewInt(*replayToPtr)
+		}
+		err = client.Replay(chain, clients, events, ks, flags, opts)
+		if err != nil {
+			logger.FatalError("replay failed: %s", err)
+		}
+		return
 	} else if addAuthority.Parsed() {
 		for _, name := range chains {
 			chain := client.FindChainByName(name, clients)
@@ -394,34 +857,363 @@ func main() {
 		return
 	}
 
-	/* channels */
-	doneClient := make(chan bool)
+	if !noListen {
+		startBlocks := parseStartBlockFlag(*startBlockPtr)
+		for _, chain := range clients {
+			override, ok := startBlocks[chain.Name]
+			if !ok {
+				override = startBlocks["*"]
+			}
+			chain.StartBlock = resolveStartBlock(chain, events, override, config.Chain[chain.Name].StartBlock)
 
-	/* wait group for interrupt handling */
-	wg := new(sync.WaitGroup)
-	wg.Add(len(clients))
+			if chain.ContractType == client.BridgeContract {
+				err = client.CheckAuthority(chain)
+				if err != nil {
+					logger.FatalError("%s", err)
+				}
+			}
+			if chain.ContractType == client.HomeContract {
+				err = client.CheckHomeBridge(chain)
+				if err != nil {
+					logger.FatalError("%s", err)
+				}
+			}
+			if chain.ContractType == client.WrappedContract {
+				err = client.CheckWrappedBridge(chain)
+				if err != nil {
+					logger.FatalError("%s", err)
+				}
+				if chain.Origin == nil {
+					logger.FatalError("origin chain of the wrapped token on %s must also be listened to", chain.Name)
+				}
+			}
+		}
+
+		// every listener stops when ctx is cancelled, on an interrupt or when the listener, metrics or api fail
+		ctx, cancel := context.WithCancel(context.Background())
+		go handleSignals(cancel)
+		failed := make(chan error, len(clients)+2)
+
+		if *metricsPtr != "" {
+			go func() {
+				logger.Info("serving metrics at http://%s/metrics", *metricsPtr)
+				err := client.ServeMetrics(*metricsPtr)
+				if err != nil {
+					logger.Error("could not serve metrics: %s", err)
+					failed <- err
+					cancel()
+				}
+			}()
+		}
+
+		// deposits seen and relayed, so they are not relayed again after a restart
+		err = client.OpenStore("log/deposits.json")
+		if err != nil {
+			logger.FatalError("could not open deposit store: %s", err)
+		}
+
+		if config.Api != nil && config.Api.Addr != "" {
+			api, err := client.NewApi(config.Api.Token, clients)
+			if err != nil {
+				logger.FatalError("could not start api: %s", err)
+			}
+			go func() {
+				logger.Info("serving admin api at http://%s", config.Api.Addr)
+				err := api.Serve(ctx, config.Api.Addr)
+				if err != nil {
+					logger.Error("could not serve api: %s", err)
+					failed <- err
+					cancel()
+				}
+			}()
+		}
+
+		// deposits are relayed by a worker per destination chain
+		client.StartRelayPool(*queueSizePtr)
 
-	if !noListen {
 		/* listener */
 		logger.Info("listening for events...")
+		wg := new(sync.WaitGroup)
 		for _, chain := range clients {
 			chains := removeChain(clients, chain)
-			go client.Listen(chain, chains, events, doneClient, ks, flags, wg)
+			wg.Add(1)
+			go func(chain *client.Chain, chains []*client.Chain) {
+				defer wg.Done()
+				err := client.Listen(ctx, chain, chains, events, ks, flags)
+				if err != nil {
+					logger.With(logger.Fields{"chain": chain.Name}).Error("listener stopped: %s", err)
+					failed <- err
+					cancel()
+				}
+			}(chain, chains)
 		}
+		wg.Wait()
+		client.StopRelayPool()
 
-		<-doneClient
+		// withdraws already sent are given a chance to be mined; the rest are tracked again on the next start
+		if n := client.WaitForPending(*shutdownTimeoutPtr); n != 0 {
+			logger.Warn("%d withdraws still pending at shutdown", n)
+		}
+		if len(failed) != 0 {
+			os.Exit(1)
+		}
+		logger.Info("shut down cleanly")
+	}
+}
+
+const accountUsage = `usage: ChainBridge account <command> [flags]
+	new                 create a new key
+	import              import a hex private key, from --key-file or typed in
+	import-mnemonic     import the key at --path of a bip-39 mnemonic and passphrase, from --mnemonic-file and --passphrase-file or typed in
+	list                list the accounts in the keystore
+	export              set the from account of --chain, or every chain, in the config to --address`
+
+// accountCommand manages the keys in the keystore; the files are those geth reads and writes
+func accountCommand(args []string) {
+	if len(args) == 0 {
+		logger.FatalError(accountUsage)
+	}
+	command := flag.NewFlagSet("account "+args[0], flag.ExitOnError)
+	keystorePath := command.String("keystore", "./keystore", "path to the keystore directory")
+	passwordFile := command.String("password-file", "", "file only readable by its owner holding the password to encrypt the key with")
+	configPath := command.String("config", "./config.json", "path to the config file, for export")
+	keyFile := command.String("key-file", "", "file holding the hex private key to import; typed in if not set")
+	mnemonicFile := command.String("mnemonic-file", "", "file holding the mnemonic to import; typed in if not set")
+	passphraseFile := command.String("passphrase-file", "", "file holding the bip-39 passphrase of the mnemonic; typed in if the mnemonic is, empty otherwise")
+	path := command.String("path", client.DefaultDerivationPath, "derivation path of the key to import from the mnemonic")
+	address := command.String("address", "", "address to export; the only account in the keystore if not set")
+	chain := command.String("chain", "", "comma separated chains to export the address to; every chain if not set")
+	command.Parse(args[1:])
+
+	ks := newKeyStore(*keystorePath)
+	passwords := &client.PasswordSource{File: *passwordFile, Prompt: true}
+
+	switch args[0] {
+	case "new":
+		password, err := passwords.NewPassword()
+		if err != nil {
+			logger.FatalError("%s", err)
+		}
+		account, err := ks.NewAccount(password)
+		if err != nil {
+			logger.FatalError("could not create key: %s", err)
+		}
+		fmt.Printf("created account %s in %s\n", account.Address.Hex(), account.URL.Path)
+	case "import":
+		key, err := readSecret(*keyFile, "private key: ")
+		if err != nil {
+			logger.FatalError("could not read private key: %s", err)
+		}
+		password, err := passwords.NewPassword()
+		if err != nil {
+			logger.FatalError("%s", err)
+		}
+		account, err := client.ImportPrivateKey(ks, key, password)
+		if err != nil {
+			logger.FatalError("could not import key: %s", err)
+		}
+		fmt.Printf("imported account %s to %s\n", account.Address.Hex(), account.URL.Path)
+	case "import-mnemonic":
+		mnemonic, err := readSecret(*mnemonicFile, "mnemonic: ")
+		if err != nil {
+			logger.FatalError("could not read mnemonic: %s", err)
+		}
+		passphrase := ""
+		if *passphraseFile != "" || *mnemonicFile == "" {
+			passphrase, err = readSecret(*passphraseFile, "passphrase (empty if none): ")
+			if err != nil {
+				logger.FatalError("could not read passphrase: %s", err)
+			}
+		}
+		password, err := passwords.NewPassword()
+		if err != nil {
+			logger.FatalError("%s", err)
+		}
+		account, err := client.ImportMnemonic(ks, mnemonic, passphrase, *path, password)
+		if err != nil {
+			logger.FatalError("could not import key: %s", err)
+		}
+		fmt.Printf("imported account %s at %s to %s\n", account.Address.Hex(), *path, account.URL.Path)
+	case "list":
+		for i, account := range ks.Accounts() {
+			fmt.Printf("account %d: %s %s\n", i, account.Address.Hex(), account.URL.Path)
+		}
+	case "export":
+		from := *address
+		if from == "" {
+			if len(ks.Accounts()) != 1 {
+				logger.FatalError("there are %d accounts in %s; choose one with --address", len(ks.Accounts()), *keystorePath)
+			}
+			from = ks.Accounts()[0].Address.Hex()
+		}
+		if !common.IsHexAddress(from) {
+			logger.FatalError("invalid address %s", from)
+		}
+		chains := []string{}
+		if *chain != "" {
+			chains = strings.Split(*chain, ",")
+		}
+		exported, err := exportAddress(*configPath, common.HexToAddress(from), chains)
+		if err != nil {
+			logger.FatalError("could not export address: %s", err)
+		}
+		fmt.Printf("set from of %s to %s in %s\n", strings.Join(exported, ", "), common.HexToAddress(from).Hex(), *configPath)
+	default:
+		logger.FatalError(accountUsage)
+	}
+}
+
+// read a secret from file, or have it typed in without echoing it
+func readSecret(file string, prompt string) (string, error) {
+	if file != "" {
+		secret, err := ioutil.ReadFile(file)
+		return strings.TrimSpace(string(secret)), err
+	}
+	secret, err := console.Stdin.PromptPassword(prompt)
+	return strings.TrimSpace(secret), err
+}
+
+// exportAddress sets the from account of chains, or every chain if there are none, in the config at path.
+// the rest of the config is left as it is, in the same order
+func exportAddress(path string, address common.Address, chains []string) ([]string, error) {
+	if filepath.Ext(path) != ".json" {
+		return nil, fmt.Errorf("only a json config can be exported to; set from in %s yourself", path)
+	}
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := new(orderedObject)
+	err = json.Unmarshal(file, config)
+	if err != nil {
+		return nil, err
+	}
+	networks := new(orderedObject)
+	if raw, ok := config.values["networks"]; ok {
+		err = json.Unmarshal(raw, networks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid networks: %s", err)
+		}
+	}
+
+	if len(chains) == 0 {
+		chains = networks.keys
+	}
+	from, _ := json.Marshal(strings.ToLower(address.Hex()))
+	for _, name := range chains {
+		raw, ok := networks.values[name]
+		if !ok {
+			return nil, fmt.Errorf("chain %s is not in %s", name, path)
+		}
+		chain := new(orderedObject)
+		err = json.Unmarshal(raw, chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain %s: %s", name, err)
+		}
+		chain.set("from", from)
+		networks.set(name, chain.raw())
+	}
+	config.set("networks", networks.raw())
+
+	out, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, append(out, '\n'), 0644)
+	if err != nil {
+		return nil, err
+	}
+	return chains, os.Rename(tmp, path)
+}
+
+// a json object that keeps the order of its keys, so the config is written back as it was
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func (o *orderedObject) UnmarshalJSON(data []byte) error {
+	o.keys, o.values = nil, map[string]json.RawMessage{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		value := json.RawMessage{}
+		err = decoder.Decode(&value)
+		if err != nil {
+			return err
+		}
+		o.set(token.(string), value)
+	}
+	return nil
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(o.values[key])
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+func (o *orderedObject) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *orderedObject) raw() json.RawMessage {
+	raw, _ := o.MarshalJSON()
+	return raw
+}
+
+// cancel on the first interrupt or SIGTERM, and exit straight away on the second
+func handleSignals(cancel context.CancelFunc) {
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	sig := <-c
+	logger.Warn("received %s, shutting down...", sig)
+	cancel()
+	<-c
+	logger.FatalError("received a second signal, exiting without waiting for listeners")
+}
+
+func contains(array []string, item string) bool {
+	for _, v := range array {
+		if v == item {
+			return true
+		}
 	}
+	return false
 }
 
+// returns a copy of array without item; array itself is shared by every listener, so it is not modified
 func removeChain(array []*client.Chain, item *client.Chain) []*client.Chain {
-	for i, v := range array {
-		if v.Name == item.Name {
-			fmt.Println("inside", array[i].Name)
-			array[i] = array[len(array)-1]
-			return array[:len(array)-1]
+	chains := []*client.Chain{}
+	for _, v := range array {
+		if v.Name != item.Name {
+			chains = append(chains, v)
 		}
 	}
-	return array
+	return chains
 }
 
 // We should do this
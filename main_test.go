@@ -6,17 +6,26 @@ import (
 	"github.com/ChainSafe/ChainBridge/client"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
-
 func TestReadAbi(t *testing.T) {
 	expected := &client.Events{
-		DepositId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Deposit(address,uint256,uint256)")))).Hex(),
-		CreationId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("ContractCreation(address)")))).Hex(),
-		WithdrawId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Withdraw(address,uint256,uint256,bytes32)")))).Hex(),
-		BridgeFundedId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("BridgeFunded(address)")))).Hex(),
-		PaidId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Paid(address,uint256)")))).Hex(),
+		DepositId:           common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Deposit(address,uint256,uint256)")))).Hex(),
+		CreationId:          common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("ContractCreation(address)")))).Hex(),
+		WithdrawId:          common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Withdraw(address,uint256,uint256,bytes32)")))).Hex(),
+		BridgeFundedId:      common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("BridgeFunded(address)")))).Hex(),
+		PaidId:              common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Paid(address,uint256)")))).Hex(),
+		SignedForWithdrawId: common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("SignedForWithdraw(bytes32,address)")))).Hex(),
+		AuthorityAddedId:    common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("AuthorityAdded(address)")))).Hex(),
+		AuthorityRemovedId:  common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("AuthorityRemoved(address)")))).Hex(),
+		ThresholdUpdatedId:  common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("ThresholdUpdated(uint256)")))).Hex(),
+		MintId:              common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Mint(address,uint256,uint256,bytes32)")))).Hex(),
+		BurnId:              common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprint("Burn(address,uint256,uint256)")))).Hex(),
 	}
 
 	actual := readAbi(false)
@@ -37,6 +46,24 @@ func TestReadAbi(t *testing.T) {
 	if actual.PaidId != expected.PaidId {
 		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "PaidId", actual.PaidId, expected.DepositId))
 	}
+	if actual.SignedForWithdrawId != expected.SignedForWithdrawId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "SignedForWithdraw", actual.SignedForWithdrawId, expected.SignedForWithdrawId))
+	}
+	if actual.AuthorityAddedId != expected.AuthorityAddedId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "AuthorityAdded", actual.AuthorityAddedId, expected.AuthorityAddedId))
+	}
+	if actual.AuthorityRemovedId != expected.AuthorityRemovedId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "AuthorityRemoved", actual.AuthorityRemovedId, expected.AuthorityRemovedId))
+	}
+	if actual.ThresholdUpdatedId != expected.ThresholdUpdatedId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "ThresholdUpdated", actual.ThresholdUpdatedId, expected.ThresholdUpdatedId))
+	}
+	if actual.MintId != expected.MintId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "Mint", actual.MintId, expected.MintId))
+	}
+	if actual.BurnId != expected.BurnId {
+		out.WriteString(fmt.Sprintf("%s -- got: %v expected: %v\n", "Burn", actual.BurnId, expected.BurnId))
+	}
 
 	if out.String() != "" {
 		t.Fatalf(out.String())
@@ -53,3 +80,37 @@ func TestExists(t *testing.T) {
 		t.Fatalf("File %s does not exists", path)
 	}
 }
+
+func TestExportAddress(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	ioutil.WriteFile(path, []byte(`{"networks": {"kovan": {"url": "http://kovan", "gasPrice": 100000000000000000000, "from": "0x1"}, "ropsten": {"from": "0x1"}}}`), 0644)
+
+	address := common.HexToAddress("0xe8b7b81f281a947840de4b23f40442b3843c5f49")
+	chains, err := exportAddress(path, address, []string{"kovan"})
+	if err != nil || len(chains) != 1 {
+		t.Fatalf("exported to %v: %v", chains, err)
+	}
+	config, _ := ioutil.ReadFile(path)
+	expected := `{
+	"networks": {
+		"kovan": {
+			"url": "http://kovan",
+			"gasPrice": 100000000000000000000,
+			"from": "0xe8b7b81f281a947840de4b23f40442b3843c5f49"
+		},
+		"ropsten": {
+			"from": "0x1"
+		}
+	}
+}
+`
+	if string(config) != expected {
+		t.Fatalf("got %s expected %s", config, expected)
+	}
+
+	if _, err := exportAddress(path, address, []string{"rinkeby"}); err == nil || !strings.Contains(err.Error(), "rinkeby") {
+		t.Fatalf("exported to a chain not in the config: %v", err)
+	}
+}
@@ -6,3 +6,22 @@ solcjs \
   --output-dir ../solidity/build \
   --overwrite \
   ../solidity/contracts/Bridge.sol
+
+# the wrapped token is deployed by whoever runs a lock-mint pair, so its bindings include the bytecode.
+# needs solcjs 0.5 and abigen 1.8.20, the versions the other bindings were generated with
+solcjs \
+  --abi \
+  --bin \
+  --optimize \
+  --output-dir ../solidity/Wrapped/build \
+  ../solidity/Wrapped/Wrapped.sol
+# solcjs names its output after the path of the source
+for ext in abi bin; do
+  mv ../solidity/Wrapped/build/*Wrapped_sol_Wrapped.$ext ../solidity/Wrapped/build/Wrapped.$ext
+done
+abigen \
+  --abi ../solidity/Wrapped/build/Wrapped.abi \
+  --bin ../solidity/Wrapped/build/Wrapped.bin \
+  --pkg wrappedcontract \
+  --type Wrapped \
+  --out ../solidity/Wrapped/Wrapped.go
@@ -0,0 +1,1310 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package wrappedcontract
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = abi.U256
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// WrappedABI is the input ABI used to generate the binding from.
+const WrappedABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_spender\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"minted\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_recipient\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"},{\"name\":\"_fromChain\",\"type\":\"uint256\"},{\"name\":\"_txHash\",\"type\":\"bytes32\"}],\"name\":\"mint\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"setBridge\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_to\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_recipient\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"},{\"name\":\"_toChain\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"bridge\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_name\",\"type\":\"string\"},{\"name\":\"_symbol\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"ContractCreation\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"BridgeSet\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"_owner\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"_spender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_recipient\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"_fromChain\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"_txHash\",\"type\":\"bytes32\"}],\"name\":\"Mint\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_recipient\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_value\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"_toChain\",\"type\":\"uint256\"}],\"name\":\"Burn\",\"type\":\"event\"}]"
+
+// Wrapped is an auto generated Go binding around an Ethereum contract.
+type Wrapped struct {
+	WrappedCaller     // Read-only binding to the contract
+	WrappedTransactor // Write-only binding to the contract
+	WrappedFilterer   // Log filterer for contract events
+}
+
+// WrappedCaller is an auto generated read-only Go binding around an Ethereum contract.
+type WrappedCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WrappedTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type WrappedTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WrappedFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type WrappedFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WrappedSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type WrappedSession struct {
+	Contract     *Wrapped          // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// WrappedCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type WrappedCallerSession struct {
+	Contract *WrappedCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts  // Call options to use throughout this session
+}
+
+// WrappedTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type WrappedTransactorSession struct {
+	Contract     *WrappedTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// WrappedRaw is an auto generated low-level Go binding around an Ethereum contract.
+type WrappedRaw struct {
+	Contract *Wrapped // Generic contract binding to access the raw methods on
+}
+
+// WrappedCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type WrappedCallerRaw struct {
+	Contract *WrappedCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// WrappedTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type WrappedTransactorRaw struct {
+	Contract *WrappedTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewWrapped creates a new instance of Wrapped, bound to a specific deployed contract.
+func NewWrapped(address common.Address, backend bind.ContractBackend) (*Wrapped, error) {
+	contract, err := bindWrapped(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Wrapped{WrappedCaller: WrappedCaller{contract: contract}, WrappedTransactor: WrappedTransactor{contract: contract}, WrappedFilterer: WrappedFilterer{contract: contract}}, nil
+}
+
+// NewWrappedCaller creates a new read-only instance of Wrapped, bound to a specific deployed contract.
+func NewWrappedCaller(address common.Address, caller bind.ContractCaller) (*WrappedCaller, error) {
+	contract, err := bindWrapped(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedCaller{contract: contract}, nil
+}
+
+// NewWrappedTransactor creates a new write-only instance of Wrapped, bound to a specific deployed contract.
+func NewWrappedTransactor(address common.Address, transactor bind.ContractTransactor) (*WrappedTransactor, error) {
+	contract, err := bindWrapped(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedTransactor{contract: contract}, nil
+}
+
+// NewWrappedFilterer creates a new log filterer instance of Wrapped, bound to a specific deployed contract.
+func NewWrappedFilterer(address common.Address, filterer bind.ContractFilterer) (*WrappedFilterer, error) {
+	contract, err := bindWrapped(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedFilterer{contract: contract}, nil
+}
+
+// bindWrapped binds a generic wrapper to an already deployed contract.
+func bindWrapped(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(WrappedABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Wrapped *WrappedRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _Wrapped.Contract.WrappedCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Wrapped *WrappedRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Wrapped.Contract.WrappedTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Wrapped *WrappedRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Wrapped.Contract.WrappedTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Wrapped *WrappedCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _Wrapped.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Wrapped *WrappedTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Wrapped.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Wrapped *WrappedTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Wrapped.Contract.contract.Transact(opts, method, params...)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance( address,  address) constant returns(uint256)
+func (_Wrapped *WrappedCaller) Allowance(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "allowance", arg0, arg1)
+	return *ret0, err
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance( address,  address) constant returns(uint256)
+func (_Wrapped *WrappedSession) Allowance(arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	return _Wrapped.Contract.Allowance(&_Wrapped.CallOpts, arg0, arg1)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance( address,  address) constant returns(uint256)
+func (_Wrapped *WrappedCallerSession) Allowance(arg0 common.Address, arg1 common.Address) (*big.Int, error) {
+	return _Wrapped.Contract.Allowance(&_Wrapped.CallOpts, arg0, arg1)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf( address) constant returns(uint256)
+func (_Wrapped *WrappedCaller) BalanceOf(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "balanceOf", arg0)
+	return *ret0, err
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf( address) constant returns(uint256)
+func (_Wrapped *WrappedSession) BalanceOf(arg0 common.Address) (*big.Int, error) {
+	return _Wrapped.Contract.BalanceOf(&_Wrapped.CallOpts, arg0)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf( address) constant returns(uint256)
+func (_Wrapped *WrappedCallerSession) BalanceOf(arg0 common.Address) (*big.Int, error) {
+	return _Wrapped.Contract.BalanceOf(&_Wrapped.CallOpts, arg0)
+}
+
+// Bridge is a free data retrieval call binding the contract method 0xe78cea92.
+//
+// Solidity: function bridge() constant returns(address)
+func (_Wrapped *WrappedCaller) Bridge(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "bridge")
+	return *ret0, err
+}
+
+// Bridge is a free data retrieval call binding the contract method 0xe78cea92.
+//
+// Solidity: function bridge() constant returns(address)
+func (_Wrapped *WrappedSession) Bridge() (common.Address, error) {
+	return _Wrapped.Contract.Bridge(&_Wrapped.CallOpts)
+}
+
+// Bridge is a free data retrieval call binding the contract method 0xe78cea92.
+//
+// Solidity: function bridge() constant returns(address)
+func (_Wrapped *WrappedCallerSession) Bridge() (common.Address, error) {
+	return _Wrapped.Contract.Bridge(&_Wrapped.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() constant returns(uint8)
+func (_Wrapped *WrappedCaller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var (
+		ret0 = new(uint8)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "decimals")
+	return *ret0, err
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() constant returns(uint8)
+func (_Wrapped *WrappedSession) Decimals() (uint8, error) {
+	return _Wrapped.Contract.Decimals(&_Wrapped.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() constant returns(uint8)
+func (_Wrapped *WrappedCallerSession) Decimals() (uint8, error) {
+	return _Wrapped.Contract.Decimals(&_Wrapped.CallOpts)
+}
+
+// Minted is a free data retrieval call binding the contract method 0x8ccc5f80.
+//
+// Solidity: function minted( bytes32) constant returns(bool)
+func (_Wrapped *WrappedCaller) Minted(opts *bind.CallOpts, arg0 [32]byte) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "minted", arg0)
+	return *ret0, err
+}
+
+// Minted is a free data retrieval call binding the contract method 0x8ccc5f80.
+//
+// Solidity: function minted( bytes32) constant returns(bool)
+func (_Wrapped *WrappedSession) Minted(arg0 [32]byte) (bool, error) {
+	return _Wrapped.Contract.Minted(&_Wrapped.CallOpts, arg0)
+}
+
+// Minted is a free data retrieval call binding the contract method 0x8ccc5f80.
+//
+// Solidity: function minted( bytes32) constant returns(bool)
+func (_Wrapped *WrappedCallerSession) Minted(arg0 [32]byte) (bool, error) {
+	return _Wrapped.Contract.Minted(&_Wrapped.CallOpts, arg0)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() constant returns(string)
+func (_Wrapped *WrappedCaller) Name(opts *bind.CallOpts) (string, error) {
+	var (
+		ret0 = new(string)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "name")
+	return *ret0, err
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() constant returns(string)
+func (_Wrapped *WrappedSession) Name() (string, error) {
+	return _Wrapped.Contract.Name(&_Wrapped.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() constant returns(string)
+func (_Wrapped *WrappedCallerSession) Name() (string, error) {
+	return _Wrapped.Contract.Name(&_Wrapped.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_Wrapped *WrappedCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "owner")
+	return *ret0, err
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_Wrapped *WrappedSession) Owner() (common.Address, error) {
+	return _Wrapped.Contract.Owner(&_Wrapped.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_Wrapped *WrappedCallerSession) Owner() (common.Address, error) {
+	return _Wrapped.Contract.Owner(&_Wrapped.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() constant returns(string)
+func (_Wrapped *WrappedCaller) Symbol(opts *bind.CallOpts) (string, error) {
+	var (
+		ret0 = new(string)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "symbol")
+	return *ret0, err
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() constant returns(string)
+func (_Wrapped *WrappedSession) Symbol() (string, error) {
+	return _Wrapped.Contract.Symbol(&_Wrapped.CallOpts)
+}
+
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+//
+// Solidity: function symbol() constant returns(string)
+func (_Wrapped *WrappedCallerSession) Symbol() (string, error) {
+	return _Wrapped.Contract.Symbol(&_Wrapped.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_Wrapped *WrappedCaller) TotalSupply(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Wrapped.contract.Call(opts, out, "totalSupply")
+	return *ret0, err
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_Wrapped *WrappedSession) TotalSupply() (*big.Int, error) {
+	return _Wrapped.Contract.TotalSupply(&_Wrapped.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_Wrapped *WrappedCallerSession) TotalSupply() (*big.Int, error) {
+	return _Wrapped.Contract.TotalSupply(&_Wrapped.CallOpts)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(_spender address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactor) Approve(opts *bind.TransactOpts, _spender common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "approve", _spender, _value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(_spender address, _value uint256) returns(bool)
+func (_Wrapped *WrappedSession) Approve(_spender common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Approve(&_Wrapped.TransactOpts, _spender, _value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(_spender address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactorSession) Approve(_spender common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Approve(&_Wrapped.TransactOpts, _spender, _value)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0xf5298aca.
+//
+// Solidity: function burn(_recipient address, _value uint256, _toChain uint256) returns()
+func (_Wrapped *WrappedTransactor) Burn(opts *bind.TransactOpts, _recipient common.Address, _value *big.Int, _toChain *big.Int) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "burn", _recipient, _value, _toChain)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0xf5298aca.
+//
+// Solidity: function burn(_recipient address, _value uint256, _toChain uint256) returns()
+func (_Wrapped *WrappedSession) Burn(_recipient common.Address, _value *big.Int, _toChain *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Burn(&_Wrapped.TransactOpts, _recipient, _value, _toChain)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0xf5298aca.
+//
+// Solidity: function burn(_recipient address, _value uint256, _toChain uint256) returns()
+func (_Wrapped *WrappedTransactorSession) Burn(_recipient common.Address, _value *big.Int, _toChain *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Burn(&_Wrapped.TransactOpts, _recipient, _value, _toChain)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0xffd4397e.
+//
+// Solidity: function mint(_recipient address, _value uint256, _fromChain uint256, _txHash bytes32) returns()
+func (_Wrapped *WrappedTransactor) Mint(opts *bind.TransactOpts, _recipient common.Address, _value *big.Int, _fromChain *big.Int, _txHash [32]byte) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "mint", _recipient, _value, _fromChain, _txHash)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0xffd4397e.
+//
+// Solidity: function mint(_recipient address, _value uint256, _fromChain uint256, _txHash bytes32) returns()
+func (_Wrapped *WrappedSession) Mint(_recipient common.Address, _value *big.Int, _fromChain *big.Int, _txHash [32]byte) (*types.Transaction, error) {
+	return _Wrapped.Contract.Mint(&_Wrapped.TransactOpts, _recipient, _value, _fromChain, _txHash)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0xffd4397e.
+//
+// Solidity: function mint(_recipient address, _value uint256, _fromChain uint256, _txHash bytes32) returns()
+func (_Wrapped *WrappedTransactorSession) Mint(_recipient common.Address, _value *big.Int, _fromChain *big.Int, _txHash [32]byte) (*types.Transaction, error) {
+	return _Wrapped.Contract.Mint(&_Wrapped.TransactOpts, _recipient, _value, _fromChain, _txHash)
+}
+
+// SetBridge is a paid mutator transaction binding the contract method 0x8dd14802.
+//
+// Solidity: function setBridge(_addr address) returns()
+func (_Wrapped *WrappedTransactor) SetBridge(opts *bind.TransactOpts, _addr common.Address) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "setBridge", _addr)
+}
+
+// SetBridge is a paid mutator transaction binding the contract method 0x8dd14802.
+//
+// Solidity: function setBridge(_addr address) returns()
+func (_Wrapped *WrappedSession) SetBridge(_addr common.Address) (*types.Transaction, error) {
+	return _Wrapped.Contract.SetBridge(&_Wrapped.TransactOpts, _addr)
+}
+
+// SetBridge is a paid mutator transaction binding the contract method 0x8dd14802.
+//
+// Solidity: function setBridge(_addr address) returns()
+func (_Wrapped *WrappedTransactorSession) SetBridge(_addr common.Address) (*types.Transaction, error) {
+	return _Wrapped.Contract.SetBridge(&_Wrapped.TransactOpts, _addr)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(_to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactor) Transfer(opts *bind.TransactOpts, _to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "transfer", _to, _value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(_to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedSession) Transfer(_to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Transfer(&_Wrapped.TransactOpts, _to, _value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(_to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactorSession) Transfer(_to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.Transfer(&_Wrapped.TransactOpts, _to, _value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(_from address, _to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactor) TransferFrom(opts *bind.TransactOpts, _from common.Address, _to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.contract.Transact(opts, "transferFrom", _from, _to, _value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(_from address, _to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedSession) TransferFrom(_from common.Address, _to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.TransferFrom(&_Wrapped.TransactOpts, _from, _to, _value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(_from address, _to address, _value uint256) returns(bool)
+func (_Wrapped *WrappedTransactorSession) TransferFrom(_from common.Address, _to common.Address, _value *big.Int) (*types.Transaction, error) {
+	return _Wrapped.Contract.TransferFrom(&_Wrapped.TransactOpts, _from, _to, _value)
+}
+
+// WrappedApprovalIterator is returned from FilterApproval and is used to iterate over the raw logs and unpacked data for Approval events raised by the Wrapped contract.
+type WrappedApprovalIterator struct {
+	Event *WrappedApproval // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedApprovalIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedApproval)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedApproval)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedApprovalIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedApprovalIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedApproval represents a Approval event raised by the Wrapped contract.
+type WrappedApproval struct {
+	Owner   common.Address
+	Spender common.Address
+	Value   *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterApproval is a free log retrieval operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: e Approval(_owner indexed address, _spender indexed address, _value uint256)
+func (_Wrapped *WrappedFilterer) FilterApproval(opts *bind.FilterOpts, _owner []common.Address, _spender []common.Address) (*WrappedApprovalIterator, error) {
+
+	var _ownerRule []interface{}
+	for _, _ownerItem := range _owner {
+		_ownerRule = append(_ownerRule, _ownerItem)
+	}
+	var _spenderRule []interface{}
+	for _, _spenderItem := range _spender {
+		_spenderRule = append(_spenderRule, _spenderItem)
+	}
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "Approval", _ownerRule, _spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedApprovalIterator{contract: _Wrapped.contract, event: "Approval", logs: logs, sub: sub}, nil
+}
+
+// WatchApproval is a free log subscription operation binding the contract event 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925.
+//
+// Solidity: e Approval(_owner indexed address, _spender indexed address, _value uint256)
+func (_Wrapped *WrappedFilterer) WatchApproval(opts *bind.WatchOpts, sink chan<- *WrappedApproval, _owner []common.Address, _spender []common.Address) (event.Subscription, error) {
+
+	var _ownerRule []interface{}
+	for _, _ownerItem := range _owner {
+		_ownerRule = append(_ownerRule, _ownerItem)
+	}
+	var _spenderRule []interface{}
+	for _, _spenderItem := range _spender {
+		_spenderRule = append(_spenderRule, _spenderItem)
+	}
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "Approval", _ownerRule, _spenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedApproval)
+				if err := _Wrapped.contract.UnpackLog(event, "Approval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WrappedBridgeSetIterator is returned from FilterBridgeSet and is used to iterate over the raw logs and unpacked data for BridgeSet events raised by the Wrapped contract.
+type WrappedBridgeSetIterator struct {
+	Event *WrappedBridgeSet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedBridgeSetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedBridgeSet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedBridgeSet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedBridgeSetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedBridgeSetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedBridgeSet represents a BridgeSet event raised by the Wrapped contract.
+type WrappedBridgeSet struct {
+	Addr common.Address
+	Raw  types.Log // Blockchain specific contextual infos
+}
+
+// FilterBridgeSet is a free log retrieval operation binding the contract event 0xa49730bff544fd0b716395c592e39c6fd2d2481a19b9229b5b240483db95a495.
+//
+// Solidity: e BridgeSet(_addr address)
+func (_Wrapped *WrappedFilterer) FilterBridgeSet(opts *bind.FilterOpts) (*WrappedBridgeSetIterator, error) {
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "BridgeSet")
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedBridgeSetIterator{contract: _Wrapped.contract, event: "BridgeSet", logs: logs, sub: sub}, nil
+}
+
+// WatchBridgeSet is a free log subscription operation binding the contract event 0xa49730bff544fd0b716395c592e39c6fd2d2481a19b9229b5b240483db95a495.
+//
+// Solidity: e BridgeSet(_addr address)
+func (_Wrapped *WrappedFilterer) WatchBridgeSet(opts *bind.WatchOpts, sink chan<- *WrappedBridgeSet) (event.Subscription, error) {
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "BridgeSet")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedBridgeSet)
+				if err := _Wrapped.contract.UnpackLog(event, "BridgeSet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WrappedBurnIterator is returned from FilterBurn and is used to iterate over the raw logs and unpacked data for Burn events raised by the Wrapped contract.
+type WrappedBurnIterator struct {
+	Event *WrappedBurn // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedBurnIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedBurn)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedBurn)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedBurnIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedBurnIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedBurn represents a Burn event raised by the Wrapped contract.
+type WrappedBurn struct {
+	Recipient common.Address
+	Value     *big.Int
+	ToChain   *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterBurn is a free log retrieval operation binding the contract event 0x49995e5dd6158cf69ad3e9777c46755a1a826a446c6416992167462dad033b2a.
+//
+// Solidity: e Burn(_recipient address, _value uint256, _toChain uint256)
+func (_Wrapped *WrappedFilterer) FilterBurn(opts *bind.FilterOpts) (*WrappedBurnIterator, error) {
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "Burn")
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedBurnIterator{contract: _Wrapped.contract, event: "Burn", logs: logs, sub: sub}, nil
+}
+
+// WatchBurn is a free log subscription operation binding the contract event 0x49995e5dd6158cf69ad3e9777c46755a1a826a446c6416992167462dad033b2a.
+//
+// Solidity: e Burn(_recipient address, _value uint256, _toChain uint256)
+func (_Wrapped *WrappedFilterer) WatchBurn(opts *bind.WatchOpts, sink chan<- *WrappedBurn) (event.Subscription, error) {
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "Burn")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedBurn)
+				if err := _Wrapped.contract.UnpackLog(event, "Burn", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WrappedContractCreationIterator is returned from FilterContractCreation and is used to iterate over the raw logs and unpacked data for ContractCreation events raised by the Wrapped contract.
+type WrappedContractCreationIterator struct {
+	Event *WrappedContractCreation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedContractCreationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedContractCreation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedContractCreation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedContractCreationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedContractCreationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedContractCreation represents a ContractCreation event raised by the Wrapped contract.
+type WrappedContractCreation struct {
+	Owner common.Address
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterContractCreation is a free log retrieval operation binding the contract event 0x4db17dd5e4732fb6da34a148104a592783ca119a1e7bb8829eba6cbadef0b511.
+//
+// Solidity: e ContractCreation(_owner address)
+func (_Wrapped *WrappedFilterer) FilterContractCreation(opts *bind.FilterOpts) (*WrappedContractCreationIterator, error) {
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "ContractCreation")
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedContractCreationIterator{contract: _Wrapped.contract, event: "ContractCreation", logs: logs, sub: sub}, nil
+}
+
+// WatchContractCreation is a free log subscription operation binding the contract event 0x4db17dd5e4732fb6da34a148104a592783ca119a1e7bb8829eba6cbadef0b511.
+//
+// Solidity: e ContractCreation(_owner address)
+func (_Wrapped *WrappedFilterer) WatchContractCreation(opts *bind.WatchOpts, sink chan<- *WrappedContractCreation) (event.Subscription, error) {
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "ContractCreation")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedContractCreation)
+				if err := _Wrapped.contract.UnpackLog(event, "ContractCreation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WrappedMintIterator is returned from FilterMint and is used to iterate over the raw logs and unpacked data for Mint events raised by the Wrapped contract.
+type WrappedMintIterator struct {
+	Event *WrappedMint // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedMintIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedMint)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedMint)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedMintIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedMintIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedMint represents a Mint event raised by the Wrapped contract.
+type WrappedMint struct {
+	Recipient common.Address
+	Value     *big.Int
+	FromChain *big.Int
+	TxHash    [32]byte
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterMint is a free log retrieval operation binding the contract event 0xcf6fbb9dcea7d07263ab4f5c3a92f53af33dffc421d9d121e1c74b307e68189d.
+//
+// Solidity: e Mint(_recipient address, _value uint256, _fromChain uint256, _txHash bytes32)
+func (_Wrapped *WrappedFilterer) FilterMint(opts *bind.FilterOpts) (*WrappedMintIterator, error) {
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "Mint")
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedMintIterator{contract: _Wrapped.contract, event: "Mint", logs: logs, sub: sub}, nil
+}
+
+// WatchMint is a free log subscription operation binding the contract event 0xcf6fbb9dcea7d07263ab4f5c3a92f53af33dffc421d9d121e1c74b307e68189d.
+//
+// Solidity: e Mint(_recipient address, _value uint256, _fromChain uint256, _txHash bytes32)
+func (_Wrapped *WrappedFilterer) WatchMint(opts *bind.WatchOpts, sink chan<- *WrappedMint) (event.Subscription, error) {
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "Mint")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedMint)
+				if err := _Wrapped.contract.UnpackLog(event, "Mint", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WrappedTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the Wrapped contract.
+type WrappedTransferIterator struct {
+	Event *WrappedTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WrappedTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WrappedTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WrappedTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WrappedTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WrappedTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WrappedTransfer represents a Transfer event raised by the Wrapped contract.
+type WrappedTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: e Transfer(_from indexed address, _to indexed address, _value uint256)
+func (_Wrapped *WrappedFilterer) FilterTransfer(opts *bind.FilterOpts, _from []common.Address, _to []common.Address) (*WrappedTransferIterator, error) {
+
+	var _fromRule []interface{}
+	for _, _fromItem := range _from {
+		_fromRule = append(_fromRule, _fromItem)
+	}
+	var _toRule []interface{}
+	for _, _toItem := range _to {
+		_toRule = append(_toRule, _toItem)
+	}
+
+	logs, sub, err := _Wrapped.contract.FilterLogs(opts, "Transfer", _fromRule, _toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedTransferIterator{contract: _Wrapped.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: e Transfer(_from indexed address, _to indexed address, _value uint256)
+func (_Wrapped *WrappedFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *WrappedTransfer, _from []common.Address, _to []common.Address) (event.Subscription, error) {
+
+	var _fromRule []interface{}
+	for _, _fromItem := range _from {
+		_fromRule = append(_fromRule, _fromItem)
+	}
+	var _toRule []interface{}
+	for _, _toItem := range _to {
+		_toRule = append(_toRule, _toItem)
+	}
+
+	logs, sub, err := _Wrapped.contract.WatchLogs(opts, "Transfer", _fromRule, _toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WrappedTransfer)
+				if err := _Wrapped.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,101 @@
+pragma solidity ^0.5.0;
+
+/*
+ * To be deployed on the network you are minting into (lock => mint).
+ *
+ * ether deposited to the Bridge contract on the origin network is locked there, and the bridge
+ * mints the same amount of this token to the recipient. burning tokens emits a Burn() event,
+ * which the bridge picks up to release the locked ether on the origin network.
+ */
+
+contract Wrapped {
+	string public name;
+	string public symbol;
+	uint8 public decimals = 18;
+	uint256 public totalSupply;
+
+	address public owner;
+	address public bridge;
+
+	mapping(address => uint256) public balanceOf;
+	mapping(address => mapping(address => uint256)) public allowance;
+	mapping(bytes32 => bool) public minted; // deposit tx hash to whether it has been minted
+
+	event ContractCreation(address _owner);
+	event BridgeSet(address _addr);
+	event Transfer(address indexed _from, address indexed _to, uint256 _value);
+	event Approval(address indexed _owner, address indexed _spender, uint256 _value);
+	event Mint(address _recipient, uint _value, uint _fromChain, bytes32 _txHash);
+	event Burn(address _recipient, uint _value, uint _toChain);
+
+	constructor(string memory _name, string memory _symbol) public {
+		name = _name;
+		symbol = _symbol;
+		owner = msg.sender;
+		bridge = msg.sender;
+		emit ContractCreation(msg.sender);
+	}
+
+	modifier onlyOwner() {
+		require(msg.sender == owner);
+		_;
+	}
+
+	modifier onlyBridge() {
+		require(msg.sender == bridge);
+		_;
+	}
+
+	function setBridge(address _addr) public onlyOwner {
+		bridge = _addr;
+		emit BridgeSet(bridge);
+	}
+
+	/* erc20 */
+	function transfer(address _to, uint256 _value) public returns (bool) {
+		_transfer(msg.sender, _to, _value);
+		return true;
+	}
+
+	function approve(address _spender, uint256 _value) public returns (bool) {
+		allowance[msg.sender][_spender] = _value;
+		emit Approval(msg.sender, _spender, _value);
+		return true;
+	}
+
+	function transferFrom(address _from, address _to, uint256 _value) public returns (bool) {
+		require(allowance[_from][msg.sender] >= _value);
+		allowance[_from][msg.sender] -= _value;
+		_transfer(_from, _to, _value);
+		return true;
+	}
+
+	function _transfer(address _from, address _to, uint256 _value) internal {
+		require(_to != address(0));
+		require(balanceOf[_from] >= _value);
+		balanceOf[_from] -= _value;
+		balanceOf[_to] += _value;
+		emit Transfer(_from, _to, _value);
+	}
+
+	/* bridge functions */
+
+	// same arguments as Bridge.withdraw(), so the bridge can relay a deposit the same way
+	function mint(address _recipient, uint _value, uint _fromChain, bytes32 _txHash) public onlyBridge {
+		require(!minted[_txHash]);
+		minted[_txHash] = true;
+		totalSupply += _value;
+		balanceOf[_recipient] += _value;
+		emit Transfer(address(0), _recipient, _value);
+		emit Mint(_recipient, _value, _fromChain, _txHash);
+	}
+
+	// same arguments as Bridge.Deposit(), so the bridge can relay a burn the same way
+	function burn(address _recipient, uint _value, uint _toChain) public {
+		require(balanceOf[msg.sender] >= _value);
+		balanceOf[msg.sender] -= _value;
+		totalSupply -= _value;
+		emit Transfer(msg.sender, address(0), _value);
+		emit Burn(_recipient, _value, _toChain);
+	}
+}